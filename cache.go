@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"faasd-agent/pkg/cachekey"
+	"faasd-agent/pkg/handlers"
+	"faasd-agent/pkg/httpcache"
+)
+
+// cachePolicy returns the cache policy of the function, or the default one
+// when the function cannot be found, and the image the function runs.
+// Caching is disabled for functions whose cached results diverged too often,
+// see verify.
+func (s *server) cachePolicy(functionName string) (handlers.CachePolicy, string) {
+	policy, image := handlers.DefaultCachePolicy, ""
+	if function, err := s.invokeResolver.Lookup(functionName); err == nil {
+		policy, image = function.CachePolicy(), function.Image()
+	}
+	if cacheDisabled(functionName) {
+		policy.Enabled = false
+	}
+	return policy, image
+}
+
+// cacheKey returns the key the result of req to the function is cached under,
+// it is shared by the probe and execution paths.
+func (s *server) cacheKey(functionName string, req *http.Request, extraPath string, body []byte, policy handlers.CachePolicy) string {
+	builder := cachekey.Builder{
+		Headers:       append(append([]string{}, s.providerConfig.CacheKeyHeaders...), policy.KeyHeaders...),
+		CanonicalJSON: s.providerConfig.CacheKeyCanonicalJSON,
+	}
+	return builder.Key(functionName, req, extraPath, body)
+}
+
+// varyKey returns the key the response of req is stored under. It is baseKey,
+// unless the entry found with get under baseKey is a vary marker, then the
+// headers it names are part of the key.
+func (s *server) varyKey(get func(key string) (interface{}, bool), baseKey string, functionName string, req *http.Request, extraPath string, body []byte, policy handlers.CachePolicy) string {
+	value, found := get(baseKey)
+	if !found {
+		return baseKey
+	}
+	vary := value.(*cacheEntry).vary
+	if len(vary) == 0 {
+		return baseKey
+	}
+	return s.cacheKey(functionName, req, extraPath, body, withKeyHeaders(policy, vary))
+}
+
+// storeResult caches the response of req unless its caching headers forbid
+// it, and returns the key it is stored under.
+func (s *server) storeResult(baseKey string, functionName, image string, req *http.Request, extraPath string, body []byte, policy handlers.CachePolicy, response []byte, executionTime time.Duration) string {
+	now := time.Now()
+	decision := httpcache.EvaluateBytes(response, now, httpcache.Options{NegativeTTL: s.providerConfig.CacheNegativeTTL})
+	if !decision.Cacheable {
+		log.Printf("[Cache] not caching the response of %s: %s\n", functionName, decision.Reason)
+		return baseKey
+	}
+
+	ttl := policy.TTL
+	if decision.TTL > 0 && (ttl == 0 || decision.TTL < ttl) {
+		ttl = decision.TTL
+	}
+	entry := &cacheEntry{createdAt: now, executionTime: executionTime, functionName: functionName, image: image}
+	if err := entry.setResponse(response); err != nil {
+		log.Printf("[Cache] not caching the response of %s: %s\n", functionName, err)
+		return baseKey
+	}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+		if window := policy.StaleWindow(); window > 0 {
+			entry.staleUntil = entry.expiresAt.Add(window)
+		}
+	}
+
+	key := baseKey
+	var marker *cacheEntry
+	mutex.Lock()
+	if len(decision.Vary) > 0 {
+		marker = &cacheEntry{createdAt: now, executionTime: executionTime, vary: decision.Vary, functionName: functionName, image: image}
+		addResult(baseKey, marker)
+		key = s.cacheKey(functionName, req, extraPath, body, withKeyHeaders(policy, decision.Vary))
+	}
+	addResult(key, entry)
+	mutex.Unlock()
+
+	// disk writes are ordered by persistResult, outside of mutex, along
+	// with the hits of the results evicted by these
+	flushPending()
+	return key
+}
+
+// withKeyHeaders returns policy with headers added to its key headers.
+func withKeyHeaders(policy handlers.CachePolicy, headers []string) handlers.CachePolicy {
+	policy.KeyHeaders = append(append([]string{}, policy.KeyHeaders...), headers...)
+	return policy
+}
+
+// sweepExpired removes the expired results, past their stale window, from the
+// cache every interval until ctx is done, so they do not hold capacity until
+// they are evicted.
+func sweepExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			removed := 0
+			for _, key := range Cache.Keys() {
+				if value, found := Cache.Peek(key); found && value.(*cacheEntry).removable(now) {
+					Cache.Remove(key)
+					removed++
+				}
+			}
+			if diskCache != nil {
+				flushPending()
+				removed += diskCache.RemoveExpired(now)
+			}
+			if removed > 0 {
+				log.Printf("[Cache] removed %d expired results\n", removed)
+			}
+		}
+	}
+}
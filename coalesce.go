@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// inflight deduplicates the executions of identical tasks, keyed by cache key.
+var inflight singleflight.Group
+
+// coalescedTasks counts the tasks answered with the result of an identical
+// task in flight, they are neither cache hits nor misses.
+var coalescedTasks uint64
+
+// invocation is the result of an execution shared by coalesced tasks.
+type invocation struct {
+	response      []byte
+	executionTime time.Duration
+}
+
+// coalesce runs execute once for the tasks with the same key in flight at the
+// same time. The first task leads, the others follow and wait for its result
+// until their own ctx is done. When the leader is canceled, a follower whose
+// ctx is not done runs execute itself. shared reports whether the result is
+// the one of another task.
+func coalesce(ctx context.Context, key string, execute func() (*invocation, error)) (result *invocation, shared bool, err error) {
+	for {
+		leader := false
+		ch := inflight.DoChan(key, func() (interface{}, error) {
+			leader = true
+			return execute()
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case r := <-ch:
+			if r.Err != nil {
+				if !leader && isCanceled(r.Err) && ctx.Err() == nil {
+					continue
+				}
+				return nil, !leader, r.Err
+			}
+			return r.Val.(*invocation), !leader, nil
+		}
+	}
+}
+
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
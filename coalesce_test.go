@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// joinDelay gives the followers started by a test the time to join the task
+// of the leader in flight.
+const joinDelay = 20 * time.Millisecond
+
+type coalesceResult struct {
+	result *invocation
+	shared bool
+	err    error
+}
+
+func coalesceAsync(ctx context.Context, key string, execute func() (*invocation, error)) chan coalesceResult {
+	done := make(chan coalesceResult, 1)
+	go func() {
+		result, shared, err := coalesce(ctx, key, execute)
+		done <- coalesceResult{result, shared, err}
+	}()
+	return done
+}
+
+func Test_CoalesceSharesTheResultOfTheLeader(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var executions int64
+	execute := func() (*invocation, error) {
+		if atomic.AddInt64(&executions, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return &invocation{response: []byte("result")}, nil
+	}
+
+	leader := coalesceAsync(context.Background(), "shared", execute)
+	<-started
+	followers := []chan coalesceResult{}
+	for i := 0; i < 3; i++ {
+		followers = append(followers, coalesceAsync(context.Background(), "shared", execute))
+	}
+	time.Sleep(joinDelay)
+	close(release)
+
+	led := <-leader
+	if led.err != nil || led.shared {
+		t.Fatalf("expected the leader to get its own result, got %+v", led)
+	}
+	shared := 0
+	for _, follower := range followers {
+		followed := <-follower
+		if followed.err != nil || followed.result != led.result {
+			t.Fatalf("expected the followers to get the result of the leader, got %+v", followed)
+		}
+		if followed.shared {
+			shared++
+		}
+	}
+	// only the leader executes the task and counts as a miss, TaskAssign
+	// counts the shared results as coalesced tasks
+	if executions != 1 || shared != len(followers) {
+		t.Fatalf("expected 1 execution and %d shared results, got %d and %d", len(followers), executions, shared)
+	}
+}
+
+func Test_CoalesceFollowerRetriesWhenLeaderIsCanceled(t *testing.T) {
+	started := make(chan struct{})
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := coalesceAsync(leaderCtx, "retry", func() (*invocation, error) {
+		close(started)
+		<-leaderCtx.Done()
+		return nil, leaderCtx.Err()
+	})
+	<-started
+
+	follower := coalesceAsync(context.Background(), "retry", func() (*invocation, error) {
+		return &invocation{response: []byte("follower")}, nil
+	})
+	time.Sleep(joinDelay)
+	cancelLeader()
+
+	if led := <-leader; !errors.Is(led.err, context.Canceled) {
+		t.Fatalf("expected the leader to be canceled, got %+v", led)
+	}
+	followed := <-follower
+	if followed.err != nil || followed.shared || string(followed.result.response) != "follower" {
+		t.Fatalf("expected the follower to execute the task itself, got %+v", followed)
+	}
+}
+
+func Test_CoalesceFollowerReturnsOnItsDeadline(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	leader := coalesceAsync(context.Background(), "deadline", func() (*invocation, error) {
+		close(started)
+		<-release
+		return &invocation{response: []byte("leader")}, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), joinDelay)
+	defer cancel()
+	start := time.Now()
+	_, shared, err := coalesce(ctx, "deadline", func() (*invocation, error) {
+		t.Errorf("expected the follower not to execute the task")
+		return nil, nil
+	})
+	if err != context.DeadlineExceeded || shared {
+		t.Fatalf("expected the follower to give up on its deadline, got %v, shared: %v", err, shared)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Fatalf("expected the follower to return on its deadline, waited %v", waited)
+	}
+
+	close(release)
+	if led := <-leader; led.err != nil || string(led.result.response) != "leader" {
+		t.Fatalf("expected the leader to complete, got %+v", led)
+	}
+}
+
+func Test_CoalesceSharesErrorsOfTheLeader(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	failure := errors.New("function failed")
+	leader := coalesceAsync(context.Background(), "error", func() (*invocation, error) {
+		close(started)
+		<-release
+		return nil, failure
+	})
+	<-started
+
+	follower := coalesceAsync(context.Background(), "error", func() (*invocation, error) {
+		return &invocation{response: []byte("follower")}, nil
+	})
+	time.Sleep(joinDelay)
+	close(release)
+
+	if led := <-leader; led.err != failure || led.shared {
+		t.Fatalf("expected the leader to fail, got %+v", led)
+	}
+	if followed := <-follower; followed.err != failure || !followed.shared {
+		t.Fatalf("expected the follower to share the failure of the leader, got %+v", followed)
+	}
+}
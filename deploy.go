@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"faasd-agent/pkg/handlers"
+	"faasd-agent/pkg/types"
+	pb "faasd-agent/proto/agent"
+)
+
+var errDeployDisabled = errors.New("deploying functions is disabled on this agent, see deploy_functions")
+var errCNIUnavailable = errors.New("CNI network is not available on this agent")
+
+// checkDeploy returns why functions cannot be deployed on this agent, if so.
+func (s *server) checkDeploy() error {
+	if !s.providerConfig.DeployFunctions {
+		return errDeployDisabled
+	}
+	if s.cni == nil {
+		return errCNIUnavailable
+	}
+	return nil
+}
+
+// Deploy pulls the image of a function and starts it on this agent.
+func (s *server) Deploy(ctx context.Context, in *pb.FunctionDeployment) (*pb.FunctionDeployResponse, error) {
+	if err := s.checkDeploy(); err != nil {
+		return nil, err
+	}
+	if err := handlers.Deploy(ctx, s.client, s.cni, functionDeployment(in), s.providerConfig.SecretsPath); err != nil {
+		log.Printf("[Deploy] error deploying %s: %s\n", in.Service, err)
+		return nil, err
+	}
+	log.Printf("[Deploy] deployed %s, image: %s\n", in.Service, in.Image)
+	return &pb.FunctionDeployResponse{Message: "OK"}, nil
+}
+
+// Update replaces a function deployed on this agent.
+func (s *server) Update(ctx context.Context, in *pb.FunctionDeployment) (*pb.FunctionDeployResponse, error) {
+	if err := s.checkDeploy(); err != nil {
+		return nil, err
+	}
+	if err := handlers.Update(ctx, s.client, s.cni, functionDeployment(in), s.providerConfig.SecretsPath); err != nil {
+		log.Printf("[Update] error updating %s: %s\n", in.Service, err)
+		return nil, err
+	}
+	removed := invalidateResults(functionSelector(in.Service))
+	log.Printf("[Update] updated %s, image: %s, invalidated %d results\n", in.Service, in.Image, removed)
+	return &pb.FunctionDeployResponse{Message: "OK"}, nil
+}
+
+// Delete removes a function from this agent.
+func (s *server) Delete(ctx context.Context, in *pb.DeleteFunctionRequest) (*pb.FunctionDeployResponse, error) {
+	if err := checkNamespace(in.Namespace); err != nil {
+		return nil, err
+	}
+	if err := s.checkDeploy(); err != nil {
+		return nil, err
+	}
+	if err := handlers.Delete(ctx, s.client, s.cni, in.FunctionName); err != nil {
+		log.Printf("[Delete] error deleting %s: %s\n", in.FunctionName, err)
+		return nil, err
+	}
+	removed := invalidateResults(functionSelector(in.FunctionName))
+	log.Printf("[Delete] deleted %s, invalidated %d results\n", in.FunctionName, removed)
+	return &pb.FunctionDeployResponse{Message: "OK"}, nil
+}
+
+// functionDeployment converts the gRPC deployment request to types.FunctionDeployment.
+func functionDeployment(in *pb.FunctionDeployment) types.FunctionDeployment {
+	req := types.FunctionDeployment{
+		Service:                in.Service,
+		Image:                  in.Image,
+		EnvProcess:             in.EnvProcess,
+		EnvVars:                in.EnvVars,
+		RegistryAuth:           in.RegistryAuth,
+		Constraints:            in.Constraints,
+		Secrets:                in.Secrets,
+		ReadOnlyRootFilesystem: in.ReadOnlyRootFilesystem,
+		Namespace:              in.Namespace,
+		Limits:                 functionResources(in.Limits),
+		Requests:               functionResources(in.Requests),
+	}
+	if in.Labels != nil {
+		req.Labels = &in.Labels
+	}
+	if in.Annotations != nil {
+		req.Annotations = &in.Annotations
+	}
+	return req
+}
+
+func functionResources(in *pb.FunctionResources) *types.FunctionResources {
+	if in == nil {
+		return nil
+	}
+	return &types.FunctionResources{Memory: in.Memory, CPU: in.Cpu}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"faasd-agent/pkg/handlers"
+	"faasd-agent/pkg/types"
+	pb "faasd-agent/proto/agent"
+)
+
+// ListFunctions returns the status of every function hosted by this agent.
+func (s *server) ListFunctions(ctx context.Context, in *pb.ListFunctionsRequest) (*pb.ListFunctionsResponse, error) {
+	if err := checkNamespace(in.Namespace); err != nil {
+		return nil, err
+	}
+
+	fns, err := handlers.ReadFunctions(s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListFunctionsResponse{Functions: make([]*pb.FunctionStatus, len(fns))}
+	for i, fn := range fns {
+		res.Functions[i] = functionStatus(fn)
+	}
+	return res, nil
+}
+
+// GetFunction returns the status of one function hosted by this agent.
+func (s *server) GetFunction(ctx context.Context, in *pb.GetFunctionRequest) (*pb.FunctionStatus, error) {
+	if err := checkNamespace(in.Namespace); err != nil {
+		return nil, err
+	}
+
+	fn, err := handlers.ReadFunction(s.client, in.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+	return functionStatus(fn), nil
+}
+
+// functionStatus converts fn to its gRPC message, adding the invocations seen by this agent.
+func functionStatus(fn types.FunctionStatus) *pb.FunctionStatus {
+	res := &pb.FunctionStatus{
+		Name:              fn.Name,
+		Image:             fn.Image,
+		InvocationCount:   float64(tracker.Invocations(fn.Name)),
+		Replicas:          fn.Replicas,
+		AvailableReplicas: fn.AvailableReplicas,
+		Namespace:         fn.Namespace,
+	}
+	if fn.Labels != nil {
+		res.Labels = *fn.Labels
+	}
+	if fn.Annotations != nil {
+		res.Annotations = *fn.Annotations
+	}
+	return res
+}
+
+// checkNamespace rejects namespaces other than the one functions are deployed to.
+func checkNamespace(namespace string) error {
+	if len(namespace) > 0 && namespace != handlers.FunctionNamespace {
+		return fmt.Errorf("namespace %s is not supported, use %s", namespace, handlers.FunctionNamespace)
+	}
+	return nil
+}
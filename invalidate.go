@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// resultSelector selects cached results by key and, when the key is not
+// enough, by entry.
+type resultSelector struct {
+	key   func(key string) bool
+	entry func(entry *cacheEntry) bool
+}
+
+// matches reports whether the entry of key is selected.
+func (s resultSelector) matches(key string, entry *cacheEntry) bool {
+	return (s.key == nil || s.key(key)) && (s.entry == nil || s.entry(entry))
+}
+
+func functionSelector(functionName string) resultSelector {
+	return resultSelector{entry: func(e *cacheEntry) bool { return e.functionName == functionName }}
+}
+
+// InvalidateCache removes the selected results from memory and disk.
+func (s *server) InvalidateCache(ctx context.Context, in *pb.InvalidateCacheRequest) (*pb.InvalidateCacheResponse, error) {
+	selectors := 0
+	var selector resultSelector
+	if len(in.Keys) > 0 {
+		selectors++
+		keys := make(map[string]bool, len(in.Keys))
+		for _, k := range in.Keys {
+			keys[k] = true
+		}
+		selector.key = func(key string) bool { return keys[key] }
+	}
+	if len(in.FunctionName) > 0 {
+		selectors++
+		selector = functionSelector(in.FunctionName)
+	}
+	if len(in.KeyPrefix) > 0 {
+		selectors++
+		selector.key = func(key string) bool { return strings.HasPrefix(key, in.KeyPrefix) }
+	}
+	if in.All {
+		selectors++
+		selector.key = func(string) bool { return true }
+	}
+	if selectors != 1 {
+		return nil, errors.New("exactly one of keys, functionName, keyPrefix or all must be set")
+	}
+
+	removed := invalidateResults(selector)
+	log.Printf("[Cache] invalidated %d results\n", removed)
+	return &pb.InvalidateCacheResponse{Removed: int64(removed)}, nil
+}
+
+// invalidateResults removes the results matching selector from both tiers
+// and returns their number, a result in both tiers counts once.
+func invalidateResults(selector resultSelector) int {
+	tierMutex.Lock()
+	defer tierMutex.Unlock()
+
+	removed := map[string]bool{}
+	for _, key := range Cache.Keys() {
+		if selector.key != nil && !selector.key(key) {
+			continue
+		}
+		if selector.entry != nil {
+			value, found := Cache.Peek(key)
+			if !found || !selector.entry(value.(*cacheEntry)) {
+				continue
+			}
+		}
+		if Cache.Remove(key) {
+			removed[key] = true
+		}
+	}
+
+	// the hits of the removed results are dropped with them, but evictions by
+	// concurrent adds may queue them until they leave memory
+	pendingMutex.Lock()
+	for key, entry := range pending {
+		if selector.matches(key, entry) {
+			delete(pending, key)
+		}
+	}
+	pendingMutex.Unlock()
+
+	if diskCache == nil {
+		return len(removed)
+	}
+	for _, key := range diskCache.Keys() {
+		if selector.key != nil && !selector.key(key) {
+			continue
+		}
+		if selector.entry != nil {
+			entry, found := loadResult(key, false)
+			if !found || !selector.entry(entry) {
+				continue
+			}
+		}
+		if diskCache.Remove(key) {
+			removed[key] = true
+		}
+	}
+	return len(removed)
+}
+
+// staleImage reports whether entry was produced by another image than the
+// one the function runs now. Results without image are never stale.
+func staleImage(entry *cacheEntry, image string) bool {
+	return len(entry.image) > 0 && len(image) > 0 && entry.image != image
+}
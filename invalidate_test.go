@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// storeTestResults caches one result per key of entries, the keys of onDisk
+// only on disk.
+func storeTestResults(t *testing.T, entries map[string]*cacheEntry, onDisk ...string) {
+	for key, entry := range entries {
+		addResult(key, entry)
+		persistResult(key, entry)
+	}
+	for _, key := range onDisk {
+		Cache.Remove(key)
+	}
+}
+
+func cachedKeys() []string {
+	keys := map[string]bool{}
+	for _, key := range Cache.Keys() {
+		keys[key] = true
+	}
+	if diskCache != nil {
+		for _, key := range diskCache.Keys() {
+			keys[key] = true
+		}
+	}
+	sorted := []string{}
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func Test_InvalidateCacheSelectors(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		request   *pb.InvalidateCacheRequest
+		remaining []string
+		removed   int64
+	}{
+		{
+			name:      "keys",
+			request:   &pb.InvalidateCacheRequest{Keys: []string{"resize-a", "thumb-a", "missing"}},
+			remaining: []string{"resize-b"},
+			removed:   2,
+		},
+		{
+			name:      "function name",
+			request:   &pb.InvalidateCacheRequest{FunctionName: "resize"},
+			remaining: []string{"thumb-a"},
+			removed:   2,
+		},
+		{
+			name:      "key prefix",
+			request:   &pb.InvalidateCacheRequest{KeyPrefix: "resize-"},
+			remaining: []string{"thumb-a"},
+			removed:   2,
+		},
+		{
+			name:      "all",
+			request:   &pb.InvalidateCacheRequest{All: true},
+			remaining: []string{},
+			removed:   3,
+		},
+	}
+
+	for _, withDisk := range []bool{false, true} {
+		for _, s := range scenarios {
+			t.Run(fmt.Sprintf("%s, disk: %v", s.name, withDisk), func(t *testing.T) {
+				setupTestCache(t, withDisk)
+				var onDisk []string
+				if withDisk {
+					onDisk = []string{"resize-b", "thumb-a"}
+				}
+				storeTestResults(t, map[string]*cacheEntry{
+					"resize-a": testEntry(t, "resize", "", "a"),
+					"resize-b": testEntry(t, "resize", "", "b"),
+					"thumb-a":  testEntry(t, "thumb", "", "a"),
+				}, onDisk...)
+
+				res, err := (&server{}).InvalidateCache(context.Background(), s.request)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				if res.Removed != s.removed {
+					t.Fatalf("expected %d removed results, got %d", s.removed, res.Removed)
+				}
+				if keys := cachedKeys(); !reflect.DeepEqual(keys, s.remaining) {
+					t.Fatalf("expected %v to remain, got %v", s.remaining, keys)
+				}
+			})
+		}
+	}
+}
+
+func Test_InvalidateCacheRequiresExactlyOneSelector(t *testing.T) {
+	setupTestCache(t, false)
+	storeTestResults(t, map[string]*cacheEntry{"resize-a": testEntry(t, "resize", "", "a")})
+
+	requests := []*pb.InvalidateCacheRequest{
+		{},
+		{Keys: []string{"resize-a"}, All: true},
+		{FunctionName: "resize", KeyPrefix: "resize-"},
+		{Keys: []string{"resize-a"}, FunctionName: "resize", KeyPrefix: "resize-", All: true},
+	}
+	for _, request := range requests {
+		if _, err := (&server{}).InvalidateCache(context.Background(), request); err == nil {
+			t.Fatalf("expected an error for %v", request)
+		}
+	}
+	if Cache.Len() != 1 {
+		t.Fatalf("expected a rejected request not to remove results")
+	}
+}
+
+func Test_InvalidateResultsDropsPendingWrites(t *testing.T) {
+	setupTestCache(t, true)
+
+	entry := testEntry(t, "resize", "", "a")
+	addResult("resize-a", entry)
+	invalidateResults(functionSelector("resize"))
+	persistResult("resize-a", entry)
+
+	if keys := cachedKeys(); len(keys) != 0 {
+		t.Fatalf("expected the invalidated result not to be written to disk, got %v", keys)
+	}
+}
+
+func Test_StaleImage(t *testing.T) {
+	scenarios := []struct {
+		entryImage string
+		image      string
+		stale      bool
+	}{
+		{entryImage: "resize:1", image: "resize:1", stale: false},
+		{entryImage: "resize:1", image: "resize:2", stale: true},
+		{entryImage: "", image: "resize:2", stale: false},
+		{entryImage: "resize:1", image: "", stale: false},
+	}
+	for _, s := range scenarios {
+		entry := &cacheEntry{image: s.entryImage}
+		if stale := staleImage(entry, s.image); stale != s.stale {
+			t.Fatalf("expected staleImage(%q, %q) to be %v", s.entryImage, s.image, s.stale)
+		}
+	}
+}
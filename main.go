@@ -7,38 +7,50 @@ import (
 	"crypto/sha1"
 	"encoding/csv"
 	"encoding/hex"
+	"faasd-agent/pkg/cache"
+	"faasd-agent/pkg/cninetwork"
+	"faasd-agent/pkg/compress"
 	"faasd-agent/pkg/handlers"
+	"faasd-agent/pkg/httpcache"
+	"faasd-agent/pkg/metrics"
 	"faasd-agent/pkg/proxy"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
-	"strings"
+	"os/signal"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru"
-
 	"faasd-agent/pkg/config"
+	"faasd-agent/pkg/filestore"
 	"faasd-agent/pkg/types"
 	pb "faasd-agent/proto/agent"
 	"log"
 	"net"
 
 	"github.com/containerd/containerd"
+	gocni "github.com/containerd/go-cni"
 	"google.golang.org/grpc"
 )
 
 const (
 	port                 = ":50051"
-	MaxCacheItem         = 4
-	FileCacheSize        = 4
 	UseCache             = true
 	SupportCacheChecking = false
 	FileCaching          = false
 	WriteToCSV           = false
-	IP                   = "192.168.43.220"
+	// maxProxyTries is how many times a task is sent to a function that cannot be reached
+	maxProxyTries = 3
+	// shutdownTimeout is how long running tasks get to finish when the agent stops
+	shutdownTimeout = 10 * time.Second
+	// latencyWindowSize is the number of recent executions kept per function for latency percentiles
+	latencyWindowSize = 256
+	// cacheSweepInterval is how often expired results are removed from the cache
+	cacheSweepInterval = time.Minute
 )
 
 type Function struct {
@@ -52,25 +64,143 @@ type Function struct {
 	annotations map[string]string
 }
 
-// server is used to implement helloworld.GreeterServer.
+// cacheEntry is a cached response together with what it cost to produce it.
+type cacheEntry struct {
+	response      []byte
+	createdAt     time.Time
+	executionTime time.Duration
+	// expiresAt is when the response stops being served fresh, zero means never
+	expiresAt time.Time
+	// staleUntil is when the expired response stops being kept to be served
+	// stale, zero when it is not, see handlers.CachePolicy.StaleWindow
+	staleUntil time.Time
+	// vary marks an entry without response, stored under the base key of
+	// responses which depend on these request headers, see varyKey
+	vary []string
+
+	functionName string
+	// image is the function image which produced the response, a result of
+	// another image is stale
+	image string
+	// hits counts the tasks answered with the entry, updated atomically
+	hits uint64
+	// storedHits is the hits written to disk with the entry, updated atomically
+	storedHits uint64
+	// encoding is the compression of response, empty when stored as is
+	encoding string
+	// rawSize is the size of response before compression, or of the file
+	// of a stored input file entry without response
+	rawSize int64
+	// origin describes the origin response of an input file
+	origin filestore.Origin
+}
+
+// setResponse stores response in the entry, compressed when it is large
+// enough, see compressor.
+func (e *cacheEntry) setResponse(response []byte) error {
+	stored, encoding, err := compressor.Compress(response)
+	if err != nil {
+		return err
+	}
+	e.response, e.rawSize = stored, int64(len(response))
+	e.encoding = ""
+	if encoding != compress.None {
+		e.encoding = encoding
+	}
+	return nil
+}
+
+// body returns the response of the entry, decompressed.
+func (e *cacheEntry) body() ([]byte, error) {
+	return compress.Decompress(e.encoding, e.response)
+}
+
+// size is what the entry counts against the cache capacity, the stored bytes.
+func (e *cacheEntry) size() int64 {
+	size := int64(len(e.response))
+	for _, name := range e.vary {
+		size += int64(len(name))
+	}
+	return size
+}
+
+// logicalSize is the size of the entry with its response decompressed.
+func (e *cacheEntry) logicalSize() int64 {
+	if e.rawSize == 0 {
+		return e.size()
+	}
+	return e.size() - int64(len(e.response)) + e.rawSize
+}
+
+// expired reports whether the entry must not be served fresh at now.
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// keepUntil is when the entry can be removed, zero means never.
+func (e *cacheEntry) keepUntil() time.Time {
+	if e.staleUntil.After(e.expiresAt) {
+		return e.staleUntil
+	}
+	return e.expiresAt
+}
+
+// removable reports whether the entry can be neither served fresh nor stale at now.
+func (e *cacheEntry) removable(now time.Time) bool {
+	keepUntil := e.keepUntil()
+	return !keepUntil.IsZero() && now.After(keepUntil)
+}
+
+// staleFor reports whether the expired entry is at most window past its
+// expiry at now.
+func (e *cacheEntry) staleFor(now time.Time, window time.Duration) bool {
+	return window > 0 && !now.After(e.expiresAt.Add(window))
+}
+
+// Cost is the execution time a cache hit saves, in seconds.
+func (e *cacheEntry) Cost() float64 {
+	return e.executionTime.Seconds()
+}
+
+// server is used to implement agent.TasksRequestServer.
 type server struct {
 	pb.UnimplementedTasksRequestServer
 	csvWriter *csv.Writer
 	filesSize map[string]string
+
+	faasConfig     *types.FaaSConfig
+	providerConfig *config.ProviderConfig
+	client         *containerd.Client
+	cni            gocni.CNI
+	proxyClient    *http.Client
+	invokeResolver functionResolver
+}
+
+// functionResolver resolves the functions tasks are sent to, it is
+// implemented by handlers.InvokeResolver.
+type functionResolver interface {
+	Resolve(functionName string) (url.URL, handlers.Function, error)
+	Lookup(functionName string) (handlers.Function, error)
+	Invalidate(functionName string)
+	Watch(ctx context.Context)
 }
 
-var Cache *lru.Cache
+var Cache cache.Cache
+
+// compressor compresses the results stored in Cache and diskCache.
+var compressor *compress.Compressor
 var mutex sync.Mutex
 var cacheHit uint64
-var cacheMiss uint
+var cacheMiss uint64
 var cacheHitFault int64
 var cacheHitRequests int64
 var totalReceiveNetworkTime int64
 var totalExecutionTime int64
 var numberOfTasks int64
 var numberOfReadFile int64
+var tracker = metrics.NewTracker(latencyWindowSize)
 
-// SayHello implements helloworld.GreeterServer
+// TaskAssign executes a task, or returns its result from the cache
 func (s *server) TaskAssign(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
 	receivingNetworkDelay := time.Now().UnixNano() - in.TimeNanoSecond
 	atomic.AddInt64(&numberOfTasks, 1)
@@ -79,39 +209,37 @@ func (s *server) TaskAssign(ctx context.Context, in *pb.TaskRequest) (*pb.TaskRe
 		atomic.AddInt64(&cacheHitRequests, 1)
 	}
 
+	// Deprecated cache probing through TaskAssign, kept for older schedulers, see ProbeCache.
 	if len(in.RequestHashes) > 0 {
 		res := &pb.TaskResponse{Message: "OK", Responses: make([][]byte, len(in.RequestHashes))}
 		if SupportCacheChecking {
 			log.Printf("New Task checking cache, len(requests): %v \n", len(in.RequestHashes))
-			// cacheHit := 0
 			for i, reqHash := range in.RequestHashes {
+				entry, found := probeEntry(reqHash)
+				if !found {
+					continue
+				}
 				if FileCaching {
-					reqHash = strings.Replace(reqHash, "http://mvatandoosts.ir/assets/images/", "", 1)
-					_, found := serverProxy.Cache.Get(reqHash)
-					if found {
-						res.Responses[i] = make([]byte, 5)
-						atomic.AddUint64(&cacheHit, 1)
-					}
-					// log.Printf("checking cache, cacheHit: %v, reqHash: %v \n", cacheHit, reqHash)
+					res.Responses[i] = make([]byte, 5)
 				} else {
-					value, found := Cache.Get(reqHash)
-					if found {
-						res.Responses[i] = value.([]byte)
-						atomic.AddUint64(&cacheHit, 1)
+					response, err := entry.body()
+					if err != nil {
+						log.Printf("[Cache] cannot decompress %s: %s\n", reqHash, err)
+						continue
 					}
-
+					res.Responses[i] = response
 				}
-
+				atomic.AddUint64(&cacheHit, 1)
 			}
 			log.Printf("checking cache, cacheHit: %v \n", cacheHit)
 			return res, nil
 		}
 	}
+	defer tracker.Begin(in.FunctionName)()
+
 	atomic.AddInt64(&totalReceiveNetworkTime, receivingNetworkDelay)
 	log.Printf("New Task Received: %v, totalReceiveNetworkTime: %v, numberOfTasks: %v, receivingNetworkDelay: %v",
 		in.FunctionName, totalReceiveNetworkTime, numberOfTasks, receivingNetworkDelay)
-	var sReqHash string
-	var fInputs string
 	req, err := unserializeReq(in.SerializeReq)
 	if err != nil {
 		log.Printf("failed unserializeReq:  %s: %s\n", in.FunctionName, err.Error())
@@ -122,21 +250,102 @@ func (s *server) TaskAssign(ctx context.Context, in *pb.TaskRequest) (*pb.TaskRe
 	if err != nil {
 		log.Println("read request bodey error :", err.Error())
 	}
-	req.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
 
 	// ******** cache
+	policy, image := handlers.DefaultCachePolicy, ""
 	if UseCache && !FileCaching {
+		policy, image = s.cachePolicy(in.FunctionName)
+	}
+	return s.assignTask(ctx, in, req, bodyBytes, policy, image)
+}
+
+// assignTask executes the task of req with body, or returns its result from
+// the cache, following the cache policy of the function which runs image.
+func (s *server) assignTask(ctx context.Context, in *pb.TaskRequest, req *http.Request, bodyBytes []byte, policy handlers.CachePolicy, image string) (*pb.TaskResponse, error) {
+	var sReqHash, baseKey string
+	var err error
+	proxyBody := bodyBytes
+	if FileCaching && len(bodyBytes) > 0 {
+		proxyBody = serverProxy.Origins.Rewrite(bodyBytes, serverProxy.BaseURL)
+	}
+
+	cacheable := UseCache && !FileCaching && policy.Enabled
+	// stale is the expired result served when the function fails, or while
+	// it is revalidated
+	var stale *cacheEntry
+	execute := func(ctx context.Context) (*invocation, error) {
+		sRes, seconds, err := s.invoke(ctx, in.FunctionName, in.ExteraPath, req, proxyBody)
+		if err != nil {
+			return nil, err
+		}
 
-		sReqHash = hash(append([]byte(in.FunctionName), bodyBytes...))
+		atomic.AddInt64(&totalExecutionTime, seconds.Milliseconds())
+		tracker.Observe(in.FunctionName, seconds)
+		if in.FunctionName == "face-detect-pigo" || in.FunctionName == "face-blur" {
+			atomic.AddInt64(&numberOfReadFile, 1)
+		}
+		// *************** cache
+		if cacheable {
+			key := sReqHash
+			// a failure must not replace the stale result it falls back to
+			fallback := stale != nil && httpcache.Failed(sRes)
+			if !fallback && (policy.MaxEntryBytes == 0 || int64(len(sRes)) <= policy.MaxEntryBytes) {
+				key = s.storeResult(baseKey, in.FunctionName, image, req, in.ExteraPath, bodyBytes, policy, sRes, seconds)
+			}
+			atomic.AddUint64(&cacheMiss, 1)
+			log.Printf("Mohammad %s took %f seconds, sRes length: %v, cacheMiss : %v, sReqHash : %v, totalExecutionTime: %v, numberOfReadFile: %v, cacheHitFault: %v, , cacheHitRequests: %v \n",
+				in.FunctionName, seconds.Seconds(), len(sRes), cacheMiss, key, totalExecutionTime, numberOfReadFile, cacheHitFault, cacheHitRequests)
+		}
+		return &invocation{response: sRes, executionTime: seconds}, nil
+	}
+
+	if cacheable {
+
+		baseKey = s.cacheKey(in.FunctionName, req, in.ExteraPath, bodyBytes, policy)
 		mutex.Lock()
-		res, found := Cache.Get(sReqHash)
+		sReqHash = s.varyKey(getResult, baseKey, in.FunctionName, req, in.ExteraPath, bodyBytes, policy)
+		res, found := getResult(sReqHash)
+		revalidating := false
+		if now := time.Now(); found && res.(*cacheEntry).expired(now) {
+			entry := res.(*cacheEntry)
+			switch {
+			case entry.staleFor(now, policy.StaleWhileRevalidate):
+				stale, revalidating = entry, true
+			case entry.staleFor(now, policy.StaleIfError):
+				stale, found = entry, false
+			default:
+				removeResult(sReqHash)
+				found = false
+			}
+		}
+		// an expired result served stale must not come from an older image either
+		if res != nil && staleImage(res.(*cacheEntry), image) {
+			removed := invalidateResults(functionSelector(in.FunctionName))
+			log.Printf("[Cache] image of %s changed to %s, invalidated %d results\n", in.FunctionName, image, removed)
+			found, stale, revalidating = false, nil, false
+		}
 
 		if found {
-			cacheHit++
+			entry := res.(*cacheEntry)
 			mutex.Unlock()
-			log.Printf("Found in cache: %v, cacheHit: %v, cacheHitFault: %v, cacheHitRequests: %v",
-				in.FunctionName, cacheHit, cacheHitFault, cacheHitRequests)
-			return &pb.TaskResponse{Message: "OK", Response: res.([]byte)}, nil
+			response, err := entry.body()
+			if err != nil {
+				removeResult(sReqHash)
+				log.Printf("[Cache] cannot decompress the result of %s: %s\n", in.FunctionName, err)
+				return nil, err
+			}
+			atomic.AddUint64(&cacheHit, 1)
+			atomic.AddUint64(&entry.hits, 1)
+			tracker.ObserveCacheHit(in.FunctionName, entry.executionTime)
+			if revalidating {
+				atomic.AddUint64(&staleHits, 1)
+				go revalidate(in.FunctionName, sReqHash, execute)
+			} else {
+				s.sampleVerification(in.FunctionName, in.ExteraPath, req, bodyBytes, sReqHash, entry)
+			}
+			log.Printf("Found in cache: %v, cacheHit: %v, cacheHitFault: %v, cacheHitRequests: %v, saved: %fs, stored: %d bytes, logical: %d bytes, stale: %v",
+				in.FunctionName, cacheHit, cacheHitFault, cacheHitRequests, entry.executionTime.Seconds(), entry.size(), entry.logicalSize(), revalidating)
+			return &pb.TaskResponse{Message: "OK", Response: response}, nil
 		}
 
 		if in.CacheHit {
@@ -146,138 +355,192 @@ func (s *server) TaskAssign(ctx context.Context, in *pb.TaskRequest) (*pb.TaskRe
 		mutex.Unlock()
 	}
 
-	if FileCaching {
-		bodyBytes, err := ioutil.ReadAll(req.Body)
-		if err != nil {
-			log.Println("read request bodey error :", err.Error())
-		}
-		if len(bodyBytes) > 0 {
-			fInputs = string(bodyBytes)
-			fInputs = strings.ReplaceAll(fInputs, "mvatandoosts.ir", IP+":"+serverProxy.Port)
-			// log.Printf("prepare inputs for proxy: %v, fInputs: %v", in.FunctionName, fInputs)
-			bodyBytes = []byte(fInputs)
-		}
-		req.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	var result *invocation
+	shared := false
+	if cacheable {
+		result, shared, err = coalesce(ctx, sReqHash, func() (*invocation, error) { return execute(ctx) })
+	} else {
+		result, err = execute(ctx)
+	}
+	if stale != nil && (err != nil || httpcache.Failed(result.response)) {
+		return serveStale(in.FunctionName, stale, err)
 	}
-
-	faasConfig, providerConfig, err := config.ReadFromEnv(types.OsEnv{})
 	if err != nil {
-		log.Printf("failed to ReadFromEnv: %v", err)
 		return nil, err
 	}
-	// log.Printf("Containerd socket: %v", providerConfig.Sock)
-
-	tryCounter := 0
-	var sRes []byte
-	var seconds time.Duration
-	for {
-		client, err := containerd.New(providerConfig.Sock)
-		if err != nil {
-			log.Printf("failed containerd.New:  %s: %s\n", in.FunctionName, err.Error())
-			return nil, err
-		}
-		defer client.Close()
+	if shared {
+		atomic.AddUint64(&coalescedTasks, 1)
+		log.Printf("Coalesced with an identical task in flight: %v, coalescedTasks: %v\n",
+			in.FunctionName, atomic.LoadUint64(&coalescedTasks))
+		return &pb.TaskResponse{Message: "OK", Response: result.response}, nil
+	}
+	sRes, seconds := result.response, result.executionTime
 
-		invokeResolver := handlers.NewInvokeResolver(client)
+	if WriteToCSV {
+		s.csvWriter.Write([]string{in.FunctionName, string(bodyBytes), s.filesSize[string(bodyBytes)],
+			fmt.Sprint(seconds.Seconds())})
+		s.csvWriter.Flush()
+	}
+	// for metric := range function.MetricChannel {
+	// 	err := printMetricData(metric)
+	// 	if err != nil {
+	// 		continue
+	// 	}
+	// }
+	return &pb.TaskResponse{Message: "OK", Response: sRes}, nil
+}
 
-		functionAddr, _, resolveErr := invokeResolver.Resolve(in.FunctionName)
+// invoke resolves the function and proxies req with body to it, retrying when
+// the function cannot be reached. It returns the serialized response and the
+// execution time of the function.
+func (s *server) invoke(ctx context.Context, functionName, extraPath string, req *http.Request, body []byte) ([]byte, time.Duration, error) {
+	for tryCounter := 1; ; tryCounter++ {
+		functionAddr, _, resolveErr := s.invokeResolver.Resolve(functionName)
 		if resolveErr != nil {
 			// TODO: Should record the 404/not found error in Prometheus.
-			log.Printf("resolver error: cannot find %s: %s\n", in.FunctionName, resolveErr.Error())
-			return nil, resolveErr
+			log.Printf("resolver error: cannot find %s: %s\n", functionName, resolveErr.Error())
+			return nil, 0, resolveErr
 		}
 
-		start := time.Now()
-		proxyClient := proxy.NewProxyClientFromConfig(*faasConfig)
-
-		proxyReq, err := proxy.BuildProxyRequest(req, functionAddr, in.ExteraPath)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		proxyReq, err := proxy.BuildProxyRequest(req, functionAddr, extraPath)
 		if err != nil {
-			// function.CloseChannel <- struct{}{}
-			log.Printf("failed proxyReq:  %s: %s\n", in.FunctionName, err.Error())
-			return nil, err
-		}
-		if proxyReq.Body != nil {
-			defer proxyReq.Body.Close()
+			log.Printf("failed proxyReq:  %s: %s\n", functionName, err.Error())
+			return nil, 0, err
 		}
 
-		response, err := proxyClient.Do(proxyReq.WithContext(ctx))
-		seconds = time.Since(start)
-		// function.CloseChannel <- struct{}{}
+		start := time.Now()
+		response, err := s.proxyClient.Do(proxyReq.WithContext(ctx))
+		seconds := time.Since(start)
 		if err != nil {
-			log.Printf("error with proxy %s request to: %s, %s, seconds: %v\n", in.FunctionName,
+			log.Printf("error with proxy %s request to: %s, %s, seconds: %v\n", functionName,
 				proxyReq.URL.String(), err.Error(), seconds.Seconds())
-			tryCounter++
-			if tryCounter > 2 {
-				log.Printf("****** Second TIme error with proxy %s request to: %s, err:%s, bodyBytes: %s, seconds: %v \n",
-					in.FunctionName, proxyReq.URL.String(), err.Error(), fInputs, seconds.Seconds())
-				return nil, err
+			// the endpoint may be stale, resolve it again
+			s.invokeResolver.Invalidate(functionName)
+			if tryCounter >= maxProxyTries || ctx.Err() != nil {
+				log.Printf("****** giving up proxy %s request to: %s, err:%s, tries: %v, seconds: %v \n",
+					functionName, proxyReq.URL.String(), err.Error(), tryCounter, seconds.Seconds())
+				return nil, seconds, err
 			}
-			// defer response.Body.Close()
-			// time.Sleep(50 * time.Millisecond)
 			continue
 		}
-		defer response.Body.Close()
-		//bodyBytes, err := ioutil.ReadAll(response.Body)
-		//if err != nil {
-		//	log.Printf("error in reading response body: %s \n", err)
-		//	return nil, err
-		//}
-		//
-		//bodyString := string(bodyBytes)
-
-		sRes, err = captureRequestData(response)
+
+		sRes, err := captureRequestData(response)
+		response.Body.Close()
 		if err != nil {
 			log.Printf("error in serializing response: %s \n", err)
-			return nil, err
+			return nil, seconds, err
 		}
+		return sRes, seconds, nil
+	}
+}
 
-		break
+// newServer reads the configuration and creates the clients shared by every
+// task for the lifetime of the agent.
+func newServer() (*server, error) {
+	faasConfig, providerConfig, err := config.ReadFromEnv(types.OsEnv{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ReadFromEnv: %v", err)
 	}
-	atomic.AddInt64(&totalExecutionTime, seconds.Milliseconds())
-	if in.FunctionName == "face-detect-pigo" || in.FunctionName == "face-blur" {
-		atomic.AddInt64(&numberOfReadFile, 1)
+
+	Cache, err = cache.New(providerConfig.CachePolicy, providerConfig.CacheSize, onResultEvicted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create result cache: %v", err)
 	}
-	// *************** cache
-	if UseCache && !FileCaching {
-		mutex.Lock()
-		Cache.Add(sReqHash, sRes)
-		mutex.Unlock()
-		cacheMiss++
-		log.Printf("Mohammad %s took %f seconds, sRes length: %v, cacheMiss : %v, sReqHash : %v, totalExecutionTime: %v, numberOfReadFile: %v, cacheHitFault: %v, , cacheHitRequests: %v \n",
-			in.FunctionName, seconds.Seconds(), len(sRes), cacheMiss, sReqHash, totalExecutionTime, numberOfReadFile, cacheHitFault, cacheHitRequests)
+	log.Printf("Result cache policy: %s, size: %d bytes\n", providerConfig.CachePolicy, providerConfig.CacheSize)
+
+	compressor, err = compress.New(providerConfig.CacheCompression, providerConfig.CacheCompressionMinSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create result compressor: %v", err)
 	}
+	log.Printf("Result compression: %s, from %d bytes\n", providerConfig.CacheCompression, providerConfig.CacheCompressionMinSize)
 
-	if WriteToCSV {
-		s.csvWriter.Write([]string{in.FunctionName, string(bodyBytes), s.filesSize[string(bodyBytes)],
-			fmt.Sprint(seconds.Seconds())})
-		s.csvWriter.Flush()
+	if len(providerConfig.DiskCachePath) > 0 {
+		diskCache, err = cache.OpenDisk(providerConfig.DiskCachePath, providerConfig.DiskCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open disk cache: %v", err)
+		}
+		log.Printf("Disk cache: %s, size: %d bytes, reloaded %d results\n",
+			providerConfig.DiskCachePath, providerConfig.DiskCacheSize, diskCache.Len())
 	}
-	// for metric := range function.MetricChannel {
-	// 	err := printMetricData(metric)
-	// 	if err != nil {
-	// 		continue
-	// 	}
-	// }
-	return &pb.TaskResponse{Message: "OK", Response: sRes}, nil
+
+	client, err := containerd.New(providerConfig.Sock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to containerd at %s: %v", providerConfig.Sock, err)
+	}
+
+	// CNI is only needed to deploy and start functions, the agent can still
+	// run tasks without it.
+	var cni gocni.CNI
+	if providerConfig.DeployFunctions || providerConfig.ScaleFromZero {
+		if cni, err = cninetwork.InitNetwork(); err != nil {
+			log.Printf("CNI network is not available, functions cannot be deployed nor started: %v", err)
+		}
+	}
+
+	invokeResolver := handlers.NewCachedInvokeResolver(client, providerConfig.ResolverTTL)
+	if providerConfig.ScaleFromZero && cni != nil {
+		invokeResolver.EnableScaleFromZero(cni, providerConfig.ReadyTimeout, tracker.ObserveColdStart)
+	}
+
+	return &server{
+		faasConfig:     faasConfig,
+		providerConfig: providerConfig,
+		client:         client,
+		cni:            cni,
+		proxyClient:    proxy.NewProxyClientFromConfig(*faasConfig),
+		invokeResolver: invokeResolver,
+	}, nil
+}
+
+// Close releases the clients of the server and closes the disk cache.
+func (s *server) Close() error {
+	s.proxyClient.CloseIdleConnections()
+	if diskCache != nil {
+		flushPending()
+		if err := diskCache.Close(); err != nil {
+			log.Printf("error closing disk cache: %v", err)
+		}
+	}
+	if serverProxy != nil && serverProxy.Store != nil {
+		if err := serverProxy.Store.Close(); err != nil {
+			log.Printf("error closing file store: %v", err)
+		}
+	}
+	return s.client.Close()
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		if err := runSnapshotCommand(os.Args[2:]); err != nil {
+			log.Fatalf("snapshot: %v", err)
+		}
+		return
+	}
+
 	cacheHit = 0
 	cacheMiss = 0
-	log.Printf("Cache Size: %v, UseCache: %v, SupportCacheChecking: %v, FileCaching: %v, FileCacheSize: %v \n",
-		MaxCacheItem, UseCache, SupportCacheChecking, FileCaching, FileCacheSize)
+	log.Printf("UseCache: %v, SupportCacheChecking: %v, FileCaching: %v \n",
+		UseCache, SupportCacheChecking, FileCaching)
 
 	if len(os.Args) < 2 {
 		log.Fatalf("Provid port nummber")
 	}
-	Cache, _ = lru.New(MaxCacheItem)
 	lis, err := net.Listen("tcp", ":"+os.Args[1])
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
+	agent, err := newServer()
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
+	defer agent.Close()
+
 	if FileCaching {
-		RunProxy(os.Args[2])
+		if err := RunProxy(os.Args[2], agent.providerConfig); err != nil {
+			log.Fatalf("failed to start the file proxy: %v", err)
+		}
 	}
 
 	s := grpc.NewServer()
@@ -316,10 +579,34 @@ func main() {
 		filesSize["http://mvatandoosts.ir/assets/images/I28.jpg"] = "13017"
 		filesSize["http://mvatandoosts.ir/assets/images/I29.jpg"] = "14955"
 		filesSize["http://mvatandoosts.ir/assets/images/I30.jpg"] = "14013"
-		pb.RegisterTasksRequestServer(s, &server{csvWriter: csvwriter, filesSize: filesSize})
-	} else {
-		pb.RegisterTasksRequestServer(s, &server{})
+		agent.csvWriter = csvwriter
+		agent.filesSize = filesSize
 	}
+	pb.RegisterTasksRequestServer(s, agent)
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go agent.invokeResolver.Watch(watchCtx)
+	go sweepExpired(watchCtx, cacheSweepInterval)
+	go hostSampler.Run(watchCtx, hostSampleInterval)
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		log.Printf("Shutting down, waiting up to %v for running tasks", shutdownTimeout)
+
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			s.Stop()
+		}
+	}()
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
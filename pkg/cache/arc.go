@@ -0,0 +1,187 @@
+package cache
+
+// arcCache is an adaptive replacement cache measured in bytes. It splits the
+// capacity between entries seen once (t1) and entries seen again (t2), and
+// moves the target size of t1 towards whichever list the ghost entries of
+// recently evicted keys (b1, b2) show would have produced a hit.
+type arcCache struct {
+	capacity int64
+	// p is the target size of t1 in bytes
+	p int64
+
+	t1 *sizedList
+	t2 *sizedList
+	b1 *sizedList
+	b2 *sizedList
+
+	onEvict EvictCallback
+}
+
+func newARC(capacity int64, onEvict EvictCallback) *arcCache {
+	return &arcCache{
+		capacity: capacity,
+		t1:       newSizedList(),
+		t2:       newSizedList(),
+		b1:       newSizedList(),
+		b2:       newSizedList(),
+		onEvict:  onEvict,
+	}
+}
+
+func (c *arcCache) Get(key string) (interface{}, bool) {
+	if it, ok := c.t1.remove(key); ok {
+		c.t2.add(key, it)
+		return it.value, true
+	}
+	if it, ok := c.t2.get(key); ok {
+		return it.value, true
+	}
+	return nil, false
+}
+
+func (c *arcCache) Peek(key string) (interface{}, bool) {
+	if it, ok := c.t1.peek(key); ok {
+		return it.value, true
+	}
+	if it, ok := c.t2.peek(key); ok {
+		return it.value, true
+	}
+	return nil, false
+}
+
+func (c *arcCache) Add(key string, value interface{}, size int64) bool {
+	_, inT1 := c.t1.remove(key)
+	_, inT2 := c.t2.remove(key)
+	if size > c.capacity {
+		c.b1.remove(key)
+		c.b2.remove(key)
+		return false
+	}
+	it := &item{value: value, size: size}
+
+	// seen before while cached, it is frequently used
+	if inT1 || inT2 {
+		c.replace(size, false)
+		c.t2.add(key, it)
+		c.trimGhosts()
+		return true
+	}
+
+	// recently evicted from t1, t1 should have been larger
+	if ghost, ok := c.b1.remove(key); ok {
+		delta := ghost.size
+		if c.b2.size > c.b1.size && c.b1.size > 0 {
+			delta = ghost.size * c.b2.size / c.b1.size
+		}
+		c.p = min64(c.p+delta, c.capacity)
+		c.replace(size, false)
+		c.t2.add(key, it)
+		c.trimGhosts()
+		return true
+	}
+
+	// recently evicted from t2, t2 should have been larger
+	if ghost, ok := c.b2.remove(key); ok {
+		delta := ghost.size
+		if c.b1.size > c.b2.size && c.b2.size > 0 {
+			delta = ghost.size * c.b1.size / c.b2.size
+		}
+		c.p = max64(c.p-delta, 0)
+		c.replace(size, true)
+		c.t2.add(key, it)
+		c.trimGhosts()
+		return true
+	}
+
+	c.replace(size, false)
+	c.t1.add(key, it)
+	c.trimGhosts()
+	return true
+}
+
+// replace evicts entries to the ghost lists until needed bytes fit.
+func (c *arcCache) replace(needed int64, inB2 bool) {
+	for c.t1.size+c.t2.size+needed > c.capacity {
+		if c.t1.len() > 0 && (c.t1.size > c.p || (c.t1.size == c.p && inB2) || c.t2.len() == 0) {
+			c.evict(c.t1, c.b1)
+		} else {
+			c.evict(c.t2, c.b2)
+		}
+	}
+}
+
+// evict moves the oldest entry of from to the ghost list to.
+func (c *arcCache) evict(from, to *sizedList) {
+	k, it, ok := from.removeOldest()
+	if !ok {
+		return
+	}
+	to.add(k, &item{size: it.size})
+	if c.onEvict != nil {
+		c.onEvict(k, it.value)
+	}
+}
+
+// trimGhosts drops the oldest ghosts to keep the bounds of ARC: t1 and b1
+// together describe at most the capacity, and the four lists at most twice
+// the capacity.
+func (c *arcCache) trimGhosts() {
+	for c.t1.size+c.b1.size > c.capacity && c.b1.len() > 0 {
+		c.b1.removeOldest()
+	}
+	for c.t1.size+c.t2.size+c.b1.size+c.b2.size > 2*c.capacity {
+		if c.b2.len() > 0 {
+			c.b2.removeOldest()
+		} else if c.b1.len() > 0 {
+			c.b1.removeOldest()
+		} else {
+			break
+		}
+	}
+}
+
+func (c *arcCache) Remove(key string) bool {
+	_, inT1 := c.t1.remove(key)
+	_, inT2 := c.t2.remove(key)
+	c.b1.remove(key)
+	c.b2.remove(key)
+	return inT1 || inT2
+}
+
+func (c *arcCache) Keys() []string {
+	return append(c.t1.keys(), c.t2.keys()...)
+}
+
+func (c *arcCache) Len() int {
+	return c.t1.len() + c.t2.len()
+}
+
+func (c *arcCache) Size() int64 {
+	return c.t1.size + c.t2.size
+}
+
+func (c *arcCache) Capacity() int64 {
+	return c.capacity
+}
+
+func (c *arcCache) Purge() {
+	c.t1.purge()
+	c.t2.purge()
+	c.b1.purge()
+	c.b2.purge()
+	c.p = 0
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+// Package cache provides caches bounded by the total size of their values in
+// bytes, with interchangeable eviction policies.
+package cache
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Eviction policies accepted by New.
+const (
+	PolicyLRU = "lru"
+	PolicyARC = "arc"
+	Policy2Q  = "2q"
+	PolicyLFU = "lfu"
+	PolicyGDS = "gds"
+	// PolicyCost evicts by saved execution time per byte and frequency, and
+	// only admits entries expected to save more time than they evict
+	PolicyCost = "cost"
+)
+
+// Policies lists the eviction policies accepted by New.
+var Policies = []string{PolicyLRU, PolicyARC, Policy2Q, PolicyLFU, PolicyGDS, PolicyCost}
+
+// Cache is a cache bounded by the total size of its values in bytes.
+type Cache interface {
+	// Get returns the value of key and records the access.
+	Get(key string) (interface{}, bool)
+	// Peek returns the value of key without recording the access.
+	Peek(key string) (interface{}, bool)
+	// Add adds or replaces the value of key, evicting other entries when the
+	// capacity is exceeded. It returns false, and drops any previous value of
+	// key, when size is larger than the capacity or the policy does not admit
+	// the value.
+	Add(key string, value interface{}, size int64) bool
+	// Remove removes key without calling the eviction callback.
+	Remove(key string) bool
+	// Keys returns the cached keys.
+	Keys() []string
+	// Len returns the number of cached entries.
+	Len() int
+	// Size returns the total size of the cached values in bytes.
+	Size() int64
+	// Capacity returns the maximum total size of the cached values in bytes.
+	Capacity() int64
+	// Purge removes every entry without calling the eviction callback.
+	Purge()
+}
+
+// Coster is implemented by values which know what it costs to produce them
+// again, such as the execution time of a function. Cost-aware policies keep
+// entries with a higher cost per byte longer, values which do not implement
+// Coster have a cost of 1.
+type Coster interface {
+	Cost() float64
+}
+
+// EvictCallback is called with every entry evicted to make room for another.
+type EvictCallback func(key string, value interface{})
+
+// New creates a thread-safe cache of capacity bytes using policy.
+func New(policy string, capacity int64, onEvict EvictCallback) (Cache, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid cache capacity: %d", capacity)
+	}
+
+	var c Cache
+	switch policy {
+	case PolicyLRU:
+		c = newLRU(capacity, onEvict)
+	case PolicyARC:
+		c = newARC(capacity, onEvict)
+	case Policy2Q:
+		c = new2Q(capacity, onEvict)
+	case PolicyLFU:
+		c = newLFU(capacity, onEvict)
+	case PolicyGDS:
+		c = newGDS(capacity, onEvict)
+	case PolicyCost:
+		c = newCost(capacity, onEvict)
+	default:
+		return nil, fmt.Errorf("unknown cache policy %q, expected one of %v", policy, Policies)
+	}
+	return &lockedCache{cache: c}, nil
+}
+
+// item is a cached value with its size.
+type item struct {
+	value interface{}
+	size  int64
+}
+
+// cost returns the cost of value, see Coster.
+func cost(value interface{}) float64 {
+	if c, ok := value.(Coster); ok {
+		return c.Cost()
+	}
+	return 1
+}
+
+// lockedCache serializes the access to a cache.
+type lockedCache struct {
+	mutex sync.Mutex
+	cache Cache
+}
+
+func (l *lockedCache) Get(key string) (interface{}, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Get(key)
+}
+
+func (l *lockedCache) Peek(key string) (interface{}, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Peek(key)
+}
+
+func (l *lockedCache) Add(key string, value interface{}, size int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Add(key, value, size)
+}
+
+func (l *lockedCache) Remove(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Remove(key)
+}
+
+func (l *lockedCache) Keys() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Keys()
+}
+
+func (l *lockedCache) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Len()
+}
+
+func (l *lockedCache) Size() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cache.Size()
+}
+
+func (l *lockedCache) Capacity() int64 {
+	return l.cache.Capacity()
+}
+
+func (l *lockedCache) Purge() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.cache.Purge()
+}
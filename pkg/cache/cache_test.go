@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+type costly struct {
+	cost float64
+}
+
+func (c costly) Cost() float64 { return c.cost }
+
+func Test_NewUnknownPolicy(t *testing.T) {
+	if _, err := New("fifo", 100, nil); err == nil {
+		t.Fatalf("expected an error for an unknown policy")
+	}
+	if _, err := New(PolicyLRU, 0, nil); err == nil {
+		t.Fatalf("expected an error for a zero capacity")
+	}
+}
+
+func Test_PoliciesRespectCapacity(t *testing.T) {
+	for _, policy := range Policies {
+		evicted, rejected := 0, 0
+		c, err := New(policy, 1000, func(string, interface{}) { evicted++ })
+		if err != nil {
+			t.Fatalf("%s: %s", policy, err)
+		}
+
+		for i := 0; i < 200; i++ {
+			key := fmt.Sprintf("key-%d", i%50)
+			if _, ok := c.Get(key); !ok && !c.Add(key, i, int64(10+i%90)) {
+				rejected++
+			}
+			if c.Size() > c.Capacity() {
+				t.Fatalf("%s: size %d exceeds capacity %d", policy, c.Size(), c.Capacity())
+			}
+		}
+
+		if evicted+rejected == 0 {
+			t.Fatalf("%s: expected evictions or rejections", policy)
+		}
+		if len(c.Keys()) != c.Len() {
+			t.Fatalf("%s: %d keys for %d entries", policy, len(c.Keys()), c.Len())
+		}
+		for _, k := range c.Keys() {
+			if _, ok := c.Peek(k); !ok {
+				t.Fatalf("%s: key %s listed but not cached", policy, k)
+			}
+		}
+		c.Purge()
+		if c.Len() != 0 || c.Size() != 0 {
+			t.Fatalf("%s: expected an empty cache after purge", policy)
+		}
+	}
+}
+
+func Test_PoliciesRejectOversizedValues(t *testing.T) {
+	for _, policy := range Policies {
+		c, _ := New(policy, 100, nil)
+		c.Add("a", "a", 10)
+		if c.Add("a", "b", 101) {
+			t.Fatalf("%s: expected oversized value to be rejected", policy)
+		}
+		if _, ok := c.Peek("a"); ok {
+			t.Fatalf("%s: expected previous value to be dropped", policy)
+		}
+		if c.Size() != 0 {
+			t.Fatalf("%s: expected size 0, got %d", policy, c.Size())
+		}
+	}
+}
+
+func Test_PoliciesReplaceValue(t *testing.T) {
+	for _, policy := range Policies {
+		c, _ := New(policy, 100, nil)
+		c.Add("a", 1, 10)
+		c.Add("a", 2, 30)
+		if v, _ := c.Get("a"); v != 2 {
+			t.Fatalf("%s: expected 2, got %v", policy, v)
+		}
+		if c.Len() != 1 || c.Size() != 30 {
+			t.Fatalf("%s: expected 1 entry of 30 bytes, got %d entries of %d bytes", policy, c.Len(), c.Size())
+		}
+		if !c.Remove("a") || c.Size() != 0 {
+			t.Fatalf("%s: expected remove to empty the cache", policy)
+		}
+	}
+}
+
+func Test_LRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := New(PolicyLRU, 30, nil)
+	c.Add("a", 1, 10)
+	c.Add("b", 2, 10)
+	c.Add("c", 3, 10)
+	c.Get("a")
+	c.Add("d", 4, 10)
+
+	if _, ok := c.Peek("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	for _, k := range []string{"a", "c", "d"} {
+		if _, ok := c.Peek(k); !ok {
+			t.Fatalf("expected %s to be cached", k)
+		}
+	}
+}
+
+func Test_LRUEvictsUntilValueFits(t *testing.T) {
+	c, _ := New(PolicyLRU, 30, nil)
+	c.Add("a", 1, 10)
+	c.Add("b", 2, 10)
+	c.Add("c", 3, 10)
+	c.Add("d", 4, 25)
+
+	if c.Len() != 1 || c.Size() != 25 {
+		t.Fatalf("expected only d to remain, got %v", c.Keys())
+	}
+}
+
+func Test_LFUEvictsLeastFrequentlyUsed(t *testing.T) {
+	c, _ := New(PolicyLFU, 30, nil)
+	c.Add("a", 1, 10)
+	c.Add("b", 2, 10)
+	c.Add("c", 3, 10)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Add("d", 4, 10)
+
+	if _, ok := c.Peek("c"); ok {
+		t.Fatalf("expected c to be evicted")
+	}
+}
+
+func Test_GDSKeepsCostlyEntries(t *testing.T) {
+	c, _ := New(PolicyGDS, 30, nil)
+	c.Add("cheap", costly{1}, 10)
+	c.Add("expensive", costly{100}, 10)
+	c.Add("other", costly{10}, 10)
+	c.Add("new", costly{10}, 10)
+
+	if _, ok := c.Peek("cheap"); ok {
+		t.Fatalf("expected cheap to be evicted")
+	}
+	if _, ok := c.Peek("expensive"); !ok {
+		t.Fatalf("expected expensive to be cached")
+	}
+}
+
+func Test_ScanResistantPolicies(t *testing.T) {
+	for _, policy := range []string{PolicyARC, Policy2Q} {
+		c, _ := New(policy, 100, nil)
+		// hot entries are used twice, then a scan adds entries used once
+		for i := 0; i < 4; i++ {
+			key := fmt.Sprintf("hot-%d", i)
+			c.Add(key, i, 10)
+			c.Get(key)
+		}
+		for i := 0; i < 50; i++ {
+			c.Add(fmt.Sprintf("scan-%d", i), i, 10)
+		}
+
+		for i := 0; i < 4; i++ {
+			if _, ok := c.Peek(fmt.Sprintf("hot-%d", i)); !ok {
+				t.Fatalf("%s: expected hot-%d to survive the scan", policy, i)
+			}
+		}
+	}
+}
+
+func Test_ARCAdapts(t *testing.T) {
+	c := newARC(4, nil)
+	// t2 holds every entry, so the next miss evicts from t2 with p at 0
+	for _, key := range []string{"a", "b", "c", "d"} {
+		c.Add(key, key, 1)
+		c.Get(key)
+	}
+	c.Add("e", "e", 1)
+	if !c.b2.contains("a") {
+		t.Fatalf("expected a ghost of a in b2, got %v", c.b2.keys())
+	}
+
+	// a hit in b2 brings a back to t2 and evicts from t1
+	c.Add("a", "a", 1)
+	if _, ok := c.t2.peek("a"); !ok || !c.b1.contains("e") || c.p != 0 {
+		t.Fatalf("expected a in t2, e in b1 and p at 0, got t2 %v, b1 %v, p %d", c.t2.keys(), c.b1.keys(), c.p)
+	}
+
+	// a hit in b1 grows the target size of t1, t2 is evicted instead
+	c.Add("e", "e", 1)
+	if _, ok := c.t2.peek("e"); !ok || !c.b2.contains("b") || c.p != 1 {
+		t.Fatalf("expected e in t2, b in b2 and p at 1, got t2 %v, b2 %v, p %d", c.t2.keys(), c.b2.keys(), c.p)
+	}
+}
+
+func Test_ARCBounds(t *testing.T) {
+	c := newARC(100, nil)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key-%d", (i*7919)%97)
+		if _, ok := c.Get(key); !ok {
+			c.Add(key, i, int64(1+i%13))
+		}
+		if l1 := c.t1.size + c.b1.size; l1 > c.capacity {
+			t.Fatalf("t1 and b1 hold %d bytes, more than the capacity %d", l1, c.capacity)
+		}
+		if all := c.t1.size + c.t2.size + c.b1.size + c.b2.size; all > 2*c.capacity {
+			t.Fatalf("the four lists hold %d bytes, more than twice the capacity %d", all, c.capacity)
+		}
+		if c.p < 0 || c.p > c.capacity {
+			t.Fatalf("target size %d out of [0, %d]", c.p, c.capacity)
+		}
+	}
+}
+
+func Test_CostAdmitsEntriesSavingMoreTime(t *testing.T) {
+	c, _ := New(PolicyCost, 30, nil)
+	c.Add("a", costly{1}, 10)
+	c.Add("b", costly{1}, 10)
+	c.Add("c", costly{1}, 10)
+
+	if c.Add("cheap", costly{0.5}, 10) {
+		t.Fatalf("expected cheap entry to be rejected")
+	}
+	if c.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", c.Len())
+	}
+
+	if !c.Add("expensive", costly{5}, 10) {
+		t.Fatalf("expected expensive entry to be admitted")
+	}
+	if _, ok := c.Peek("expensive"); !ok || c.Size() > 30 {
+		t.Fatalf("expected expensive entry within capacity, got %v", c.Keys())
+	}
+}
+
+func Test_CostAdmitsFrequentlyRequestedEntries(t *testing.T) {
+	c, _ := New(PolicyCost, 20, nil)
+	c.Add("a", costly{1}, 10)
+	c.Add("b", costly{1}, 10)
+
+	// a miss for a key is remembered by the sketch even though it is not cached
+	for i := 0; i < 3; i++ {
+		c.Get("popular")
+	}
+	if !c.Add("popular", costly{1}, 10) {
+		t.Fatalf("expected frequently requested entry to be admitted")
+	}
+}
+
+func Test_SketchEstimatesAndAges(t *testing.T) {
+	s := newFrequencySketch(16)
+	for i := 0; i < 5; i++ {
+		s.increment("a")
+	}
+	if e := s.estimate("a"); e != 5 {
+		t.Fatalf("expected 5, got %d", e)
+	}
+	if e := s.estimate("b"); e != 0 {
+		t.Fatalf("expected 0, got %d", e)
+	}
+
+	s.reset()
+	if e := s.estimate("a"); e != 2 {
+		t.Fatalf("expected 2 after reset, got %d", e)
+	}
+}
@@ -0,0 +1,82 @@
+package cache
+
+const (
+	// costSketchBytesPerKey is the expected average entry size used to size
+	// the frequency sketch of the cost-aware cache
+	costSketchBytesPerKey = 4 * 1024
+	costSketchMinWidth    = 1024
+	costSketchMaxWidth    = 1 << 20
+)
+
+// costCache is a cost-aware cache. It evicts the entries saving the least
+// execution time per byte first, weighting the cost of an entry, see Coster,
+// by its estimated request frequency (GreedyDual-Size-Frequency). Like
+// TinyLFU, it remembers the frequency of keys which are not cached, and only
+// admits a new entry when it is expected to save more time than the entries
+// it would evict.
+type costCache struct {
+	*priorityCache
+	sketch *frequencySketch
+}
+
+func newCost(capacity int64, onEvict EvictCallback) *costCache {
+	width := int(capacity / costSketchBytesPerKey)
+	if width < costSketchMinWidth {
+		width = costSketchMinWidth
+	}
+	if width > costSketchMaxWidth {
+		width = costSketchMaxWidth
+	}
+
+	c := &costCache{sketch: newFrequencySketch(width)}
+	c.priorityCache = newPriorityCache(capacity, onEvict, func(p *priorityCache, e *priorityEntry) float64 {
+		return p.inflation + c.saving(e)/float64(entrySize(e))
+	})
+	c.priorityCache.admit = c.admit
+	return c
+}
+
+// Get records the request for key, whether it is cached or not.
+func (c *costCache) Get(key string) (interface{}, bool) {
+	c.sketch.increment(key)
+	return c.priorityCache.Get(key)
+}
+
+// saving is the execution time e is expected to save while cached.
+func (c *costCache) saving(e *priorityEntry) float64 {
+	frequency := c.sketch.estimate(e.key)
+	if frequency < 1 {
+		frequency = 1
+	}
+	return float64(frequency) * cost(e.item.value)
+}
+
+// admit reports whether candidate saves more time than the entries evicted to
+// make room for it.
+func (c *costCache) admit(p *priorityCache, candidate *priorityEntry) bool {
+	var victims []*priorityEntry
+	freed := int64(0)
+	for p.size-freed+candidate.item.size > p.capacity && len(p.queue) > 0 {
+		e := p.queue[0]
+		p.remove(e)
+		victims = append(victims, e)
+		freed += e.item.size
+	}
+
+	victimSaving := 0.0
+	for _, e := range victims {
+		victimSaving += c.saving(e)
+	}
+
+	for _, e := range victims {
+		p.restore(e)
+	}
+	return c.saving(candidate) > victimSaving
+}
+
+func entrySize(e *priorityEntry) int64 {
+	if e.item.size < 1 {
+		return 1
+	}
+	return e.item.size
+}
@@ -0,0 +1,317 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	diskValuesBucket = []byte("values")
+	diskIndexBucket  = []byte("index")
+)
+
+// diskOpenTimeout is how long OpenDisk waits for another process to release the database
+const diskOpenTimeout = 5 * time.Second
+
+// Disk is a persistent cache of byte values bounded by their total size,
+// evicting the least recently used values. Each value is written together
+// with its index record in one transaction, so a crash loses at most the
+// values being written, and the index is reloaded when the cache is opened.
+type Disk struct {
+	db       *bolt.DB
+	capacity int64
+
+	mutex sync.Mutex
+	// items is the recency ordered index, the items have no value
+	items *sizedList
+}
+
+// diskRecord is the index record of a value.
+type diskRecord struct {
+	size int64
+	// accessedAt orders the values by recency when the index is reloaded
+	accessedAt int64
+	// expiresAt is when the value expires in UnixNano, zero means never
+	expiresAt int64
+}
+
+func (r diskRecord) encode() []byte {
+	buf := make([]byte, 24)
+	binary.BigEndian.PutUint64(buf[0:], uint64(r.size))
+	binary.BigEndian.PutUint64(buf[8:], uint64(r.accessedAt))
+	binary.BigEndian.PutUint64(buf[16:], uint64(r.expiresAt))
+	return buf
+}
+
+func decodeDiskRecord(buf []byte) (diskRecord, error) {
+	if len(buf) != 24 {
+		return diskRecord{}, fmt.Errorf("invalid index record of %d bytes", len(buf))
+	}
+	return diskRecord{
+		size:       int64(binary.BigEndian.Uint64(buf[0:])),
+		accessedAt: int64(binary.BigEndian.Uint64(buf[8:])),
+		expiresAt:  int64(binary.BigEndian.Uint64(buf[16:])),
+	}, nil
+}
+
+// OpenDisk opens, or creates, the cache stored in the file at path and
+// reloads its index. Values beyond capacity, when it was lowered, are evicted.
+func OpenDisk(path string, capacity int64) (*Disk, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid disk cache capacity: %d", capacity)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: diskOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open disk cache %s: %s", path, err)
+	}
+
+	d := &Disk{db: db, capacity: capacity, items: newSizedList()}
+	if err := d.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// load rebuilds the index from the index bucket, dropping records without value.
+func (d *Disk) load() error {
+	type loaded struct {
+		key    string
+		record diskRecord
+	}
+	var records []loaded
+	var orphans [][]byte
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		values, err := tx.CreateBucketIfNotExists(diskValuesBucket)
+		if err != nil {
+			return err
+		}
+		index, err := tx.CreateBucketIfNotExists(diskIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		err = index.ForEach(func(k, v []byte) error {
+			record, err := decodeDiskRecord(v)
+			if err != nil || values.Get(k) == nil {
+				orphans = append(orphans, append([]byte{}, k...))
+				return nil
+			}
+			records = append(records, loaded{key: string(k), record: record})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range orphans {
+			if err := index.Delete(k); err != nil {
+				return err
+			}
+			if err := values.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to load disk cache index: %s", err)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].record.accessedAt < records[j].record.accessedAt })
+	for _, r := range records {
+		d.items.add(r.key, &item{value: r.record, size: r.record.size})
+	}
+	return d.evict(0)
+}
+
+// Get returns the value of key and marks it as the most recently used.
+func (d *Disk) Get(key string) ([]byte, bool) {
+	return d.read(key, true)
+}
+
+// Peek returns the value of key without changing its recency.
+func (d *Disk) Peek(key string) ([]byte, bool) {
+	return d.read(key, false)
+}
+
+func (d *Disk) read(key string, touch bool) ([]byte, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	found := false
+	if touch {
+		_, found = d.items.get(key)
+	} else {
+		found = d.items.contains(key)
+	}
+	if !found {
+		return nil, false
+	}
+
+	var value []byte
+	d.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(diskValuesBucket).Get([]byte(key)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if value == nil {
+		d.items.remove(key)
+		return nil, false
+	}
+	return value, true
+}
+
+// Put stores the value of key, which expires at expiresAt unless it is zero,
+// evicting the least recently used values when the capacity is exceeded.
+func (d *Disk) Put(key string, value []byte, expiresAt time.Time) error {
+	size := int64(len(value))
+	if size > d.capacity {
+		d.Remove(key)
+		return fmt.Errorf("value of %d bytes exceeds the disk cache capacity", size)
+	}
+
+	record := diskRecord{size: size, accessedAt: time.Now().UnixNano()}
+	if !expiresAt.IsZero() {
+		record.expiresAt = expiresAt.UnixNano()
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.items.remove(key)
+	if err := d.evict(size); err != nil {
+		return err
+	}
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(diskValuesBucket).Put([]byte(key), value); err != nil {
+			return err
+		}
+		return tx.Bucket(diskIndexBucket).Put([]byte(key), record.encode())
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write %s to the disk cache: %s", key, err)
+	}
+	d.items.add(key, &item{value: record, size: size})
+	return nil
+}
+
+// evict removes the least recently used values until needed bytes fit. The
+// caller must hold the mutex.
+func (d *Disk) evict(needed int64) error {
+	var keys []string
+	for d.items.size+needed > d.capacity {
+		k, _, ok := d.items.removeOldest()
+		if !ok {
+			break
+		}
+		keys = append(keys, k)
+	}
+	return d.delete(keys)
+}
+
+// delete removes keys from the database in one transaction.
+func (d *Disk) delete(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		for _, k := range keys {
+			if err := tx.Bucket(diskValuesBucket).Delete([]byte(k)); err != nil {
+				return err
+			}
+			if err := tx.Bucket(diskIndexBucket).Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Remove removes the value of key.
+func (d *Disk) Remove(key string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.items.remove(key); !ok {
+		return false
+	}
+	return d.delete([]string{key}) == nil
+}
+
+// RemoveExpired removes the values expired at now and returns their number.
+func (d *Disk) RemoveExpired(now time.Time) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var expired []string
+	for _, k := range d.items.keys() {
+		it, _ := d.items.peek(k)
+		if r := it.value.(diskRecord); r.expiresAt != 0 && now.UnixNano() > r.expiresAt {
+			expired = append(expired, k)
+		}
+	}
+	for _, k := range expired {
+		d.items.remove(k)
+	}
+	if err := d.delete(expired); err != nil {
+		return 0
+	}
+	return len(expired)
+}
+
+// Keys returns the cached keys, from the least to the most recently used.
+func (d *Disk) Keys() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.items.keys()
+}
+
+// Len returns the number of cached values.
+func (d *Disk) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.items.len()
+}
+
+// Size returns the total size of the cached values in bytes.
+func (d *Disk) Size() int64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.items.size
+}
+
+// Capacity returns the maximum total size of the cached values in bytes.
+func (d *Disk) Capacity() int64 {
+	return d.capacity
+}
+
+// Close saves the recency of the values, so it survives a restart, and closes the database.
+func (d *Disk) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		index := tx.Bucket(diskIndexBucket)
+		for i, k := range d.items.keys() {
+			it, _ := d.items.peek(k)
+			record := it.value.(diskRecord)
+			record.accessedAt = int64(i)
+			if err := index.Put([]byte(k), record.encode()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := d.db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
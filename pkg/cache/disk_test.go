@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func openTestDisk(t *testing.T, path string, capacity int64) *Disk {
+	d, err := OpenDisk(path, capacity)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	return d
+}
+
+func Test_DiskEvictsLeastRecentlyUsed(t *testing.T) {
+	d := openTestDisk(t, filepath.Join(t.TempDir(), "cache.db"), 30)
+	defer d.Close()
+
+	for _, k := range []string{"a", "b", "c"} {
+		if err := d.Put(k, []byte("0123456789"), time.Time{}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	d.Get("a")
+	d.Put("d", []byte("0123456789"), time.Time{})
+
+	if _, ok := d.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if v, ok := d.Get("a"); !ok || string(v) != "0123456789" {
+		t.Fatalf("expected a to be cached, got %q", v)
+	}
+	if d.Size() != 30 || d.Len() != 3 {
+		t.Fatalf("expected 3 values of 30 bytes, got %d values of %d bytes", d.Len(), d.Size())
+	}
+	if err := d.Put("large", make([]byte, 31), time.Time{}); err == nil {
+		t.Fatalf("expected a value larger than the capacity to be rejected")
+	}
+}
+
+func Test_DiskReloadsIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	d := openTestDisk(t, path, 100)
+	for _, k := range []string{"a", "b", "c"} {
+		d.Put(k, []byte(k), time.Time{})
+	}
+	d.Get("a")
+	if err := d.Close(); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	d = openTestDisk(t, path, 100)
+	if keys := d.Keys(); !reflect.DeepEqual(keys, []string{"b", "c", "a"}) {
+		t.Fatalf("expected [b c a], got %v", keys)
+	}
+	if v, ok := d.Get("b"); !ok || string(v) != "b" {
+		t.Fatalf("expected b to survive the restart, got %q", v)
+	}
+	d.Close()
+
+	// a lower capacity evicts the least recently used values
+	d = openTestDisk(t, path, 2)
+	defer d.Close()
+	if keys := d.Keys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Fatalf("expected [a b], got %v", keys)
+	}
+}
+
+func Test_DiskRemoveExpired(t *testing.T) {
+	d := openTestDisk(t, filepath.Join(t.TempDir(), "cache.db"), 100)
+	defer d.Close()
+
+	now := time.Now()
+	d.Put("fresh", []byte("1"), now.Add(time.Hour))
+	d.Put("stale", []byte("2"), now.Add(-time.Second))
+	d.Put("forever", []byte("3"), time.Time{})
+
+	if n := d.RemoveExpired(now); n != 1 {
+		t.Fatalf("expected 1 expired value, got %d", n)
+	}
+	if _, ok := d.Get("stale"); ok {
+		t.Fatalf("expected stale to be removed")
+	}
+	if d.Len() != 2 {
+		t.Fatalf("expected 2 values, got %d", d.Len())
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"math"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// sizedList is a recency ordered list of items which keeps track of the total
+// size of its items. It is the building block of the LRU, ARC and 2Q caches,
+// ghost lists keep items without a value.
+type sizedList struct {
+	lru  *simplelru.LRU
+	size int64
+}
+
+func newSizedList() *sizedList {
+	// the list is bounded by the caches using it, not by its number of items
+	lru, _ := simplelru.NewLRU(math.MaxInt32, nil)
+	return &sizedList{lru: lru}
+}
+
+// add adds or replaces key as the most recent item.
+func (l *sizedList) add(key string, it *item) {
+	l.remove(key)
+	l.lru.Add(key, it)
+	l.size += it.size
+}
+
+// get returns the item of key and marks it as the most recent one.
+func (l *sizedList) get(key string) (*item, bool) {
+	v, ok := l.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*item), true
+}
+
+func (l *sizedList) peek(key string) (*item, bool) {
+	v, ok := l.lru.Peek(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*item), true
+}
+
+func (l *sizedList) contains(key string) bool {
+	return l.lru.Contains(key)
+}
+
+func (l *sizedList) remove(key string) (*item, bool) {
+	it, ok := l.peek(key)
+	if !ok {
+		return nil, false
+	}
+	l.lru.Remove(key)
+	l.size -= it.size
+	return it, true
+}
+
+// removeOldest removes and returns the least recent item.
+func (l *sizedList) removeOldest() (string, *item, bool) {
+	k, v, ok := l.lru.RemoveOldest()
+	if !ok {
+		return "", nil, false
+	}
+	it := v.(*item)
+	l.size -= it.size
+	return k.(string), it, true
+}
+
+func (l *sizedList) keys() []string {
+	keys := l.lru.Keys()
+	res := make([]string, len(keys))
+	for i, k := range keys {
+		res[i] = k.(string)
+	}
+	return res
+}
+
+func (l *sizedList) len() int {
+	return l.lru.Len()
+}
+
+func (l *sizedList) purge() {
+	l.lru.Purge()
+	l.size = 0
+}
@@ -0,0 +1,69 @@
+package cache
+
+// lruCache evicts the least recently used entries.
+type lruCache struct {
+	capacity int64
+	items    *sizedList
+	onEvict  EvictCallback
+}
+
+func newLRU(capacity int64, onEvict EvictCallback) *lruCache {
+	return &lruCache{capacity: capacity, items: newSizedList(), onEvict: onEvict}
+}
+
+func (c *lruCache) Get(key string) (interface{}, bool) {
+	it, ok := c.items.get(key)
+	if !ok {
+		return nil, false
+	}
+	return it.value, true
+}
+
+func (c *lruCache) Peek(key string) (interface{}, bool) {
+	it, ok := c.items.peek(key)
+	if !ok {
+		return nil, false
+	}
+	return it.value, true
+}
+
+func (c *lruCache) Add(key string, value interface{}, size int64) bool {
+	c.items.remove(key)
+	if size > c.capacity {
+		return false
+	}
+
+	c.items.add(key, &item{value: value, size: size})
+	for c.items.size > c.capacity {
+		k, it, _ := c.items.removeOldest()
+		if c.onEvict != nil {
+			c.onEvict(k, it.value)
+		}
+	}
+	return true
+}
+
+func (c *lruCache) Remove(key string) bool {
+	_, ok := c.items.remove(key)
+	return ok
+}
+
+func (c *lruCache) Keys() []string {
+	return c.items.keys()
+}
+
+func (c *lruCache) Len() int {
+	return c.items.len()
+}
+
+func (c *lruCache) Size() int64 {
+	return c.items.size
+}
+
+func (c *lruCache) Capacity() int64 {
+	return c.capacity
+}
+
+func (c *lruCache) Purge() {
+	c.items.purge()
+}
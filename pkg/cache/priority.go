@@ -0,0 +1,199 @@
+package cache
+
+import "container/heap"
+
+// priorityEntry is an entry of a priorityCache.
+type priorityEntry struct {
+	key  string
+	item *item
+	hits uint64
+
+	priority float64
+	// seq orders entries of equal priority, the least recently used is evicted first
+	seq   uint64
+	index int
+}
+
+// priorityCache evicts the entry with the lowest priority, as computed by
+// its policy whenever the entry is added or used.
+type priorityCache struct {
+	capacity int64
+	size     int64
+	entries  map[string]*priorityEntry
+	queue    priorityQueue
+	seq      uint64
+
+	// priority computes the priority of an entry
+	priority func(c *priorityCache, e *priorityEntry) float64
+	// inflation is the priority of the last evicted entry, used by GDS to age entries
+	inflation float64
+	// admit, when set, decides whether an entry which does not fit without
+	// evicting others is cached
+	admit func(c *priorityCache, candidate *priorityEntry) bool
+
+	onEvict EvictCallback
+}
+
+// newLFU creates a cache evicting the least frequently used entries.
+func newLFU(capacity int64, onEvict EvictCallback) *priorityCache {
+	return newPriorityCache(capacity, onEvict, func(c *priorityCache, e *priorityEntry) float64 {
+		return float64(e.hits)
+	})
+}
+
+// newGDS creates a GreedyDual-Size cache, which evicts the entries with the
+// lowest cost per byte first, see Coster. Each eviction raises the priority of
+// the entries used afterwards, so entries not used for long are evicted
+// eventually, whatever their cost.
+func newGDS(capacity int64, onEvict EvictCallback) *priorityCache {
+	return newPriorityCache(capacity, onEvict, func(c *priorityCache, e *priorityEntry) float64 {
+		return c.inflation + cost(e.item.value)/float64(entrySize(e))
+	})
+}
+
+func newPriorityCache(capacity int64, onEvict EvictCallback, priority func(c *priorityCache, e *priorityEntry) float64) *priorityCache {
+	return &priorityCache{
+		capacity: capacity,
+		entries:  make(map[string]*priorityEntry),
+		priority: priority,
+		onEvict:  onEvict,
+	}
+}
+
+func (c *priorityCache) Get(key string) (interface{}, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.touch(e)
+	heap.Fix(&c.queue, e.index)
+	return e.item.value, true
+}
+
+func (c *priorityCache) Peek(key string) (interface{}, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return e.item.value, true
+}
+
+func (c *priorityCache) Add(key string, value interface{}, size int64) bool {
+	var hits uint64
+	if e, ok := c.entries[key]; ok {
+		hits = e.hits
+		c.remove(e)
+	}
+	if size > c.capacity {
+		return false
+	}
+
+	e := &priorityEntry{key: key, item: &item{value: value, size: size}, hits: hits}
+	c.touch(e)
+	if c.admit != nil && c.size+size > c.capacity && !c.admit(c, e) {
+		return false
+	}
+
+	for c.size+size > c.capacity {
+		e := heap.Pop(&c.queue).(*priorityEntry)
+		delete(c.entries, e.key)
+		c.size -= e.item.size
+		c.inflation = e.priority
+		if c.onEvict != nil {
+			c.onEvict(e.key, e.item.value)
+		}
+	}
+
+	c.restore(e)
+	return true
+}
+
+// touch records a use of e and updates its priority.
+func (c *priorityCache) touch(e *priorityEntry) {
+	c.seq++
+	e.seq = c.seq
+	e.hits++
+	e.priority = c.priority(c, e)
+}
+
+// restore caches e with its current priority.
+func (c *priorityCache) restore(e *priorityEntry) {
+	c.entries[e.key] = e
+	c.size += e.item.size
+	heap.Push(&c.queue, e)
+}
+
+func (c *priorityCache) remove(e *priorityEntry) {
+	heap.Remove(&c.queue, e.index)
+	delete(c.entries, e.key)
+	c.size -= e.item.size
+}
+
+func (c *priorityCache) Remove(key string) bool {
+	e, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.remove(e)
+	return true
+}
+
+func (c *priorityCache) Keys() []string {
+	keys := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (c *priorityCache) Len() int {
+	return len(c.entries)
+}
+
+func (c *priorityCache) Size() int64 {
+	return c.size
+}
+
+func (c *priorityCache) Capacity() int64 {
+	return c.capacity
+}
+
+func (c *priorityCache) Purge() {
+	c.entries = make(map[string]*priorityEntry)
+	c.queue = nil
+	c.size = 0
+	c.inflation = 0
+}
+
+// priorityQueue is a min-heap of entries implementing heap.Interface.
+type priorityQueue []*priorityEntry
+
+func (q priorityQueue) Len() int { return len(q) }
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].priority == q[j].priority {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].priority < q[j].priority
+}
+
+func (q priorityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *priorityQueue) Push(x interface{}) {
+	e := x.(*priorityEntry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return e
+}
@@ -0,0 +1,89 @@
+package cache
+
+import "hash/fnv"
+
+const (
+	sketchDepth = 4
+	// sketchMaxCount is where the 4-bit counters of the sketch saturate
+	sketchMaxCount = 15
+	// sketchResetFactor is the number of increments, per counter of a row,
+	// after which every counter is halved so old popularity fades
+	sketchResetFactor = 10
+)
+
+// sketchSeeds decorrelates the rows of the sketch.
+var sketchSeeds = [sketchDepth]uint64{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9, 0x94d049bb133111eb, 0x2545f4914f6cdd1d}
+
+// frequencySketch is a count-min sketch estimating how often keys were
+// requested recently, as used by TinyLFU admission.
+type frequencySketch struct {
+	rows      [sketchDepth][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+// newFrequencySketch creates a sketch sized for about width distinct keys,
+// rounded up to a power of two.
+func newFrequencySketch(width int) *frequencySketch {
+	size := 1
+	for size < width {
+		size <<= 1
+	}
+	s := &frequencySketch{mask: uint64(size - 1), resetAt: size * sketchResetFactor}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, size)
+	}
+	return s
+}
+
+// increment records a request for key.
+func (s *frequencySketch) increment(key string) {
+	h := hashKey(key)
+	for i := range s.rows {
+		idx := s.index(h, i)
+		if s.rows[i][idx] < sketchMaxCount {
+			s.rows[i][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+// estimate returns the estimated number of recent requests for key.
+func (s *frequencySketch) estimate(key string) int {
+	h := hashKey(key)
+	min := uint8(sketchMaxCount)
+	for i := range s.rows {
+		if c := s.rows[i][s.index(h, i)]; c < min {
+			min = c
+		}
+	}
+	return int(min)
+}
+
+// reset halves every counter.
+func (s *frequencySketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+func (s *frequencySketch) index(h uint64, row int) uint64 {
+	h ^= sketchSeeds[row]
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h & s.mask
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
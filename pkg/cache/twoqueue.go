@@ -0,0 +1,130 @@
+package cache
+
+const (
+	// twoQueueRecentRatio is the share of the capacity kept for entries seen once
+	twoQueueRecentRatio = 0.25
+	// twoQueueGhostRatio is the share of the capacity tracked for evicted recent entries
+	twoQueueGhostRatio = 0.50
+)
+
+// twoQueueCache is a 2Q cache measured in bytes. New entries go to the recent
+// queue and are promoted to the frequent queue when used again, either while
+// cached or shortly after their eviction, so a scan of entries used once does
+// not flush the frequently used ones.
+type twoQueueCache struct {
+	capacity     int64
+	recentTarget int64
+	ghostTarget  int64
+
+	recent      *sizedList
+	frequent    *sizedList
+	recentEvict *sizedList
+
+	onEvict EvictCallback
+}
+
+func new2Q(capacity int64, onEvict EvictCallback) *twoQueueCache {
+	return &twoQueueCache{
+		capacity:     capacity,
+		recentTarget: int64(float64(capacity) * twoQueueRecentRatio),
+		ghostTarget:  int64(float64(capacity) * twoQueueGhostRatio),
+		recent:       newSizedList(),
+		frequent:     newSizedList(),
+		recentEvict:  newSizedList(),
+		onEvict:      onEvict,
+	}
+}
+
+func (c *twoQueueCache) Get(key string) (interface{}, bool) {
+	if it, ok := c.frequent.get(key); ok {
+		return it.value, true
+	}
+	if it, ok := c.recent.remove(key); ok {
+		c.frequent.add(key, it)
+		return it.value, true
+	}
+	return nil, false
+}
+
+func (c *twoQueueCache) Peek(key string) (interface{}, bool) {
+	if it, ok := c.frequent.peek(key); ok {
+		return it.value, true
+	}
+	if it, ok := c.recent.peek(key); ok {
+		return it.value, true
+	}
+	return nil, false
+}
+
+func (c *twoQueueCache) Add(key string, value interface{}, size int64) bool {
+	_, inFrequent := c.frequent.remove(key)
+	_, inRecent := c.recent.remove(key)
+	_, inGhost := c.recentEvict.remove(key)
+	if size > c.capacity {
+		return false
+	}
+	it := &item{value: value, size: size}
+
+	if inFrequent || inRecent || inGhost {
+		c.ensureSpace(size, inGhost)
+		c.frequent.add(key, it)
+		return true
+	}
+
+	c.ensureSpace(size, false)
+	c.recent.add(key, it)
+	return true
+}
+
+// ensureSpace evicts entries until needed bytes fit, preferring the recent
+// queue while it is over its target.
+func (c *twoQueueCache) ensureSpace(needed int64, recentEvict bool) {
+	for c.recent.size+c.frequent.size+needed > c.capacity {
+		if c.recent.len() > 0 && (c.recent.size > c.recentTarget || (c.recent.size == c.recentTarget && !recentEvict) || c.frequent.len() == 0) {
+			k, it, _ := c.recent.removeOldest()
+			c.recentEvict.add(k, &item{size: it.size})
+			c.evicted(k, it)
+		} else {
+			k, it, _ := c.frequent.removeOldest()
+			c.evicted(k, it)
+		}
+	}
+	for c.recentEvict.size > c.ghostTarget {
+		c.recentEvict.removeOldest()
+	}
+}
+
+func (c *twoQueueCache) evicted(key string, it *item) {
+	if c.onEvict != nil {
+		c.onEvict(key, it.value)
+	}
+}
+
+func (c *twoQueueCache) Remove(key string) bool {
+	_, inFrequent := c.frequent.remove(key)
+	_, inRecent := c.recent.remove(key)
+	c.recentEvict.remove(key)
+	return inFrequent || inRecent
+}
+
+func (c *twoQueueCache) Keys() []string {
+	return append(c.frequent.keys(), c.recent.keys()...)
+}
+
+func (c *twoQueueCache) Len() int {
+	return c.recent.len() + c.frequent.len()
+}
+
+func (c *twoQueueCache) Size() int64 {
+	return c.recent.size + c.frequent.size
+}
+
+func (c *twoQueueCache) Capacity() int64 {
+	return c.capacity
+}
+
+func (c *twoQueueCache) Purge() {
+	c.recent.purge()
+	c.frequent.purge()
+	c.recentEvict.purge()
+}
@@ -0,0 +1,137 @@
+// Package cachekey builds the keys results are cached under from a canonical
+// form of the invoked request, so equivalent requests share a result and
+// different ones never do.
+package cachekey
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Builder builds cache keys from the method, path, sorted query, allowed
+// headers and body of a request.
+type Builder struct {
+	// Headers is the allowlist of headers which are part of the key, other
+	// headers are ignored
+	Headers []string
+	// CanonicalJSON makes JSON bodies part of the key in canonical form, with
+	// sorted object keys and without insignificant whitespace
+	CanonicalJSON bool
+}
+
+// Key returns the cache key of req to the function, extraPath being the path
+// the request is sent to and body its content.
+func (b Builder) Key(functionName string, req *http.Request, extraPath string, body []byte) string {
+	h := sha1.New()
+	writeField(h, functionName)
+	writeField(h, method(req.Method))
+	writeField(h, canonicalPath(extraPath))
+
+	query := ""
+	if req.URL != nil {
+		query = canonicalQuery(req.URL.RawQuery)
+	}
+	writeField(h, query)
+
+	for _, name := range canonicalHeaderNames(b.Headers) {
+		writeField(h, name)
+		writeField(h, strings.Join(req.Header.Values(name), ","))
+	}
+
+	if b.CanonicalJSON && isJSON(req.Header.Get("Content-Type")) {
+		body = canonicalJSON(body)
+	}
+	writeField(h, string(body))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeField writes the length of s before s, so the boundaries between
+// fields are part of the key.
+func writeField(h hash.Hash, s string) {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(s)))
+	h.Write(length[:n])
+	h.Write([]byte(s))
+}
+
+func method(m string) string {
+	if len(m) == 0 {
+		return http.MethodGet
+	}
+	return strings.ToUpper(m)
+}
+
+// canonicalPath cleans p so "a//b/" and "/a/b" are the same path.
+func canonicalPath(p string) string {
+	if len(p) == 0 {
+		return "/"
+	}
+	return path.Clean("/" + p)
+}
+
+// canonicalQuery sorts the query by parameter and value, an unparsable query
+// is used as is.
+func canonicalQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	for _, v := range values {
+		sort.Strings(v)
+	}
+	return values.Encode()
+}
+
+// canonicalHeaderNames returns the sorted canonical names of headers without duplicates.
+func canonicalHeaderNames(headers []string) []string {
+	names := make([]string, 0, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		name := http.CanonicalHeaderKey(strings.TrimSpace(header))
+		if len(name) == 0 || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// canonicalJSON re-encodes body with sorted object keys and without
+// insignificant whitespace, numbers are kept as written. An invalid body is
+// returned as is.
+func canonicalJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
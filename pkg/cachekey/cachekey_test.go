@@ -0,0 +1,110 @@
+package cachekey
+
+import (
+	"net/http"
+	"testing"
+)
+
+func newRequest(t *testing.T, method, target string, header http.Header) *http.Request {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return req
+}
+
+func Test_KeyIsStable(t *testing.T) {
+	b := Builder{}
+	req := newRequest(t, http.MethodPost, "http://gateway/function/shasum", nil)
+
+	first := b.Key("shasum", req, "", []byte("data"))
+	second := b.Key("shasum", req, "", []byte("data"))
+	if first != second {
+		t.Fatalf("expected equal keys, got %s and %s", first, second)
+	}
+	if len(first) != 40 {
+		t.Fatalf("expected a sha1 hex key, got %q", first)
+	}
+}
+
+func Test_KeyDistinguishesRequests(t *testing.T) {
+	b := Builder{Headers: []string{"Accept"}}
+	base := newRequest(t, http.MethodPost, "http://gateway/?a=1", http.Header{"Accept": {"text/plain"}})
+	baseKey := b.Key("fn", base, "/resize", []byte("body"))
+
+	cases := map[string]string{
+		"function": b.Key("other", base, "/resize", []byte("body")),
+		"method":   b.Key("fn", newRequest(t, http.MethodPut, "http://gateway/?a=1", http.Header{"Accept": {"text/plain"}}), "/resize", []byte("body")),
+		"path":     b.Key("fn", base, "/crop", []byte("body")),
+		"query":    b.Key("fn", newRequest(t, http.MethodPost, "http://gateway/?a=2", http.Header{"Accept": {"text/plain"}}), "/resize", []byte("body")),
+		"header":   b.Key("fn", newRequest(t, http.MethodPost, "http://gateway/?a=1", http.Header{"Accept": {"image/png"}}), "/resize", []byte("body")),
+		"body":     b.Key("fn", base, "/resize", []byte("other")),
+		// the boundary between fields is part of the key
+		"boundary": b.Key("fn", base, "/resiz", []byte("ebody")),
+	}
+	for name, key := range cases {
+		if key == baseKey {
+			t.Fatalf("%s: expected a different key", name)
+		}
+	}
+}
+
+func Test_KeyCanonicalizesRequests(t *testing.T) {
+	b := Builder{Headers: []string{"x-tenant-id", "Accept", "accept"}}
+
+	first := newRequest(t, "post", "http://gateway/?b=2&a=1&a=0", http.Header{
+		"Accept":      {"text/plain"},
+		"X-Tenant-Id": {"42"},
+		"User-Agent":  {"curl"},
+	})
+	second := newRequest(t, http.MethodPost, "http://gateway/?a=0&a=1&b=2", http.Header{
+		"X-Tenant-Id": {"42"},
+		"Accept":      {"text/plain"},
+		"User-Agent":  {"wget"},
+	})
+
+	if b.Key("fn", first, "resize//", nil) != b.Key("fn", second, "/resize", nil) {
+		t.Fatalf("expected equivalent requests to share a key")
+	}
+}
+
+func Test_KeyCanonicalJSON(t *testing.T) {
+	header := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	req := newRequest(t, http.MethodPost, "http://gateway/", header)
+	first := []byte(`{"b": [1, 2.50], "a": {"y": true, "x": null}}`)
+	second := []byte(`{"a":{"x":null,"y":true},"b":[1,2.50]}`)
+
+	b := Builder{}
+	if b.Key("fn", req, "", first) == b.Key("fn", req, "", second) {
+		t.Fatalf("expected raw bodies to differ without CanonicalJSON")
+	}
+
+	b.CanonicalJSON = true
+	if b.Key("fn", req, "", first) != b.Key("fn", req, "", second) {
+		t.Fatalf("expected equivalent JSON bodies to share a key")
+	}
+
+	// numbers are kept as written
+	if b.Key("fn", req, "", []byte(`{"n": 2.50}`)) == b.Key("fn", req, "", []byte(`{"n": 2.5}`)) {
+		t.Fatalf("expected numbers to be kept as written")
+	}
+
+	text := newRequest(t, http.MethodPost, "http://gateway/", http.Header{"Content-Type": {"text/plain"}})
+	if b.Key("fn", text, "", first) == b.Key("fn", text, "", second) {
+		t.Fatalf("expected non JSON bodies to be used as is")
+	}
+}
+
+func Test_CanonicalJSONKeepsInvalidBodies(t *testing.T) {
+	for _, body := range []string{`{"a": 1`, `{"a": 1} {"b": 2}`, "not json"} {
+		if got := string(canonicalJSON([]byte(body))); got != body {
+			t.Fatalf("expected %q, got %q", body, got)
+		}
+	}
+	if got := string(canonicalJSON([]byte(" [1, 2] "))); got != "[1,2]" {
+		t.Fatalf("expected [1,2], got %q", got)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net"
 	"os"
+	"path"
+	"path/filepath"
 
 	"github.com/containerd/containerd"
 	gocni "github.com/containerd/go-cni"
@@ -34,61 +37,92 @@ const (
 )
 
 // defaultCNIConf is a CNI configuration that enables network access to containers (docker-bridge style)
-// var defaultCNIConf = fmt.Sprintf(`
-// {
-//     "cniVersion": "0.4.0",
-//     "name": "%s",
-//     "plugins": [
-//       {
-//         "type": "bridge",
-//         "bridge": "%s",
-//         "isGateway": true,
-//         "ipMasq": true,
-//         "ipam": {
-//             "type": "host-local",
-//             "subnet": "%s",
-//             "routes": [
-//                 { "dst": "0.0.0.0/0" }
-//             ]
-//         }
-//       },
-//       {
-//         "type": "firewall"
-//       }
-//     ]
-// }
-// `, defaultNetworkName, defaultBridgeName, defaultSubnet)
-
-// // InitNetwork writes configlist file and initializes CNI network
-// func InitNetwork() (gocni.CNI, error) {
-
-// 	log.Printf("Writing network config...\n")
-// 	if !dirExists(CNIConfDir) {
-// 		if err := os.MkdirAll(CNIConfDir, 0755); err != nil {
-// 			return nil, fmt.Errorf("cannot create directory: %s", CNIConfDir)
-// 		}
-// 	}
-
-// 	netConfig := path.Join(CNIConfDir, defaultCNIConfFilename)
-// 	if err := ioutil.WriteFile(netConfig, []byte(defaultCNIConf), 644); err != nil {
-// 		return nil, fmt.Errorf("cannot write network config: %s", defaultCNIConfFilename)
-
-// 	}
-// 	// Initialize CNI library
-// 	cni, err := gocni.New(gocni.WithPluginConfDir(CNIConfDir),
-// 		gocni.WithPluginDir([]string{CNIBinDir}))
-
-// 	if err != nil {
-// 		return nil, fmt.Errorf("error initializing cni: %s", err)
-// 	}
-
-// 	// Load the cni configuration
-// 	if err := cni.Load(gocni.WithLoNetwork, gocni.WithConfListFile(filepath.Join(CNIConfDir, defaultCNIConfFilename))); err != nil {
-// 		return nil, fmt.Errorf("failed to load cni configuration: %v", err)
-// 	}
-
-// 	return cni, nil
-// }
+var defaultCNIConf = fmt.Sprintf(`
+{
+    "cniVersion": "0.4.0",
+    "name": "%s",
+    "plugins": [
+      {
+        "type": "bridge",
+        "bridge": "%s",
+        "isGateway": true,
+        "ipMasq": true,
+        "ipam": {
+            "type": "host-local",
+            "subnet": "%s",
+            "routes": [
+                { "dst": "0.0.0.0/0" }
+            ]
+        }
+      },
+      {
+        "type": "firewall"
+      }
+    ]
+}
+`, defaultNetworkName, defaultBridgeName, defaultSubnet)
+
+// InitNetwork writes configlist file and initializes CNI network
+func InitNetwork() (gocni.CNI, error) {
+
+	log.Printf("Writing network config unless present...\n")
+	if !dirExists(CNIConfDir) {
+		if err := os.MkdirAll(CNIConfDir, 0755); err != nil {
+			return nil, fmt.Errorf("cannot create directory: %s", CNIConfDir)
+		}
+	}
+
+	netConfig := path.Join(CNIConfDir, defaultCNIConfFilename)
+	if err := writeConfigOnce(netConfig, []byte(defaultCNIConf)); err != nil {
+		return nil, fmt.Errorf("cannot write network config: %s: %v", defaultCNIConfFilename, err)
+	}
+	// Initialize CNI library
+	cni, err := gocni.New(gocni.WithPluginConfDir(CNIConfDir),
+		gocni.WithPluginDir([]string{CNIBinDir}))
+
+	if err != nil {
+		return nil, fmt.Errorf("error initializing cni: %s", err)
+	}
+
+	// Load the cni configuration
+	if err := cni.Load(gocni.WithLoNetwork, gocni.WithConfListFile(filepath.Join(CNIConfDir, defaultCNIConfFilename))); err != nil {
+		return nil, fmt.Errorf("failed to load cni configuration: %v", err)
+	}
+
+	return cni, nil
+}
+
+// writeConfigOnce writes data to filename unless it exists already. The
+// agents of a host start together, the first one writes it whole and the
+// others keep it.
+func writeConfigOnce(filename string, data []byte) error {
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// unlike a rename, a link never replaces the config of another agent
+	if err := os.Link(tmp.Name(), filename); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return nil
+}
 
 // CreateCNINetwork creates a CNI network interface and attaches it to the context
 func CreateCNINetwork(ctx context.Context, cni gocni.CNI, task containerd.Task, labels map[string]string) (*gocni.CNIResult, error) {
@@ -102,6 +136,15 @@ func CreateCNINetwork(ctx context.Context, cni gocni.CNI, task containerd.Task,
 	return result, nil
 }
 
+// RemoveCNINetwork detaches task from the CNI network
+func RemoveCNINetwork(ctx context.Context, cni gocni.CNI, task containerd.Task) error {
+	id := netID(task)
+	if err := cni.Remove(ctx, id, netNamespace(task)); err != nil {
+		return errors.Wrapf(err, "Failed to remove network for task: %q, %v", id, err)
+	}
+	return nil
+}
+
 // DeleteCNINetwork deletes a CNI network based on task ID and Pid
 func DeleteCNINetwork(ctx context.Context, cni gocni.CNI, client *containerd.Client, name string) error {
 	container, containerErr := client.LoadContainer(ctx, name)
@@ -0,0 +1,130 @@
+// Package compress compresses cached responses with the algorithm chosen by
+// configuration, and decompresses them with the one they were stored with.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algorithms accepted by New.
+const (
+	None = "none"
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Algorithms lists the algorithms accepted by New.
+var Algorithms = []string{None, Gzip, Zstd}
+
+// Compressor compresses values of at least MinSize bytes.
+type Compressor struct {
+	algorithm string
+	minSize   int64
+}
+
+// New creates a Compressor using algorithm for values of at least minSize
+// bytes. The None algorithm stores every value as is.
+func New(algorithm string, minSize int64) (*Compressor, error) {
+	switch algorithm {
+	case None, Gzip, Zstd:
+	default:
+		return nil, fmt.Errorf("unknown compression %q, expected one of %v", algorithm, Algorithms)
+	}
+	return &Compressor{algorithm: algorithm, minSize: minSize}, nil
+}
+
+// Algorithm returns the algorithm of c.
+func (c *Compressor) Algorithm() string {
+	return c.algorithm
+}
+
+// Compress returns data compressed and the algorithm it was compressed with.
+// Values smaller than the threshold, and values which do not shrink, are
+// returned as is with the None algorithm.
+func (c *Compressor) Compress(data []byte) ([]byte, string, error) {
+	if c.algorithm == None || int64(len(data)) < c.minSize {
+		return data, None, nil
+	}
+	compressed, err := encode(c.algorithm, data)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(compressed) >= len(data) {
+		return data, None, nil
+	}
+	return compressed, c.algorithm, nil
+}
+
+// Decompress returns data, compressed with algorithm, decompressed. An empty
+// algorithm is the same as None.
+func Decompress(algorithm string, data []byte) ([]byte, error) {
+	switch algorithm {
+	case None, "":
+		return data, nil
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	case Zstd:
+		initZstd()
+		if zstdErr != nil {
+			return nil, zstdErr
+		}
+		return zstdDec.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unknown compression %q", algorithm)
+	}
+}
+
+var gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+
+var (
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+	zstdErr  error
+)
+
+func encode(algorithm string, data []byte) ([]byte, error) {
+	switch algorithm {
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(w)
+		w.Reset(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		initZstd()
+		if zstdErr != nil {
+			return nil, zstdErr
+		}
+		return zstdEnc.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", algorithm)
+}
+
+// initZstd creates the shared zstd encoder and decoder, their EncodeAll and
+// DecodeAll are safe for concurrent use.
+func initZstd() {
+	zstdOnce.Do(func() {
+		zstdEnc, zstdErr = zstd.NewWriter(nil)
+		if zstdErr == nil {
+			zstdDec, zstdErr = zstd.NewReader(nil)
+		}
+	})
+}
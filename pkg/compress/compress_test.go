@@ -0,0 +1,55 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_Compress_RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nhello world "), 100)
+
+	for _, algorithm := range []string{Gzip, Zstd} {
+		c, err := New(algorithm, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressed, used, err := c.Compress(data)
+		if err != nil {
+			t.Fatalf("%s: %s", algorithm, err)
+		}
+		if used != algorithm {
+			t.Fatalf("%s: want the value compressed, got %q", algorithm, used)
+		}
+		if len(compressed) >= len(data) {
+			t.Fatalf("%s: want fewer than %d bytes, got %d", algorithm, len(data), len(compressed))
+		}
+		decompressed, err := Decompress(used, compressed)
+		if err != nil {
+			t.Fatalf("%s: %s", algorithm, err)
+		}
+		if !bytes.Equal(decompressed, data) {
+			t.Fatalf("%s: decompressed value differs", algorithm)
+		}
+	}
+}
+
+func Test_Compress_BelowThreshold(t *testing.T) {
+	c, err := New(Zstd, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("short")
+	stored, used, err := c.Compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used != None || !bytes.Equal(stored, data) {
+		t.Fatalf("want the value stored as is, got %q", used)
+	}
+}
+
+func Test_New_UnknownAlgorithm(t *testing.T) {
+	if _, err := New("brotli", 0); err == nil {
+		t.Fatal("want an error for an unknown algorithm")
+	}
+}
@@ -9,6 +9,80 @@ import (
 type ProviderConfig struct {
 	// Sock is the address of the containerd socket
 	Sock string
+
+	// SecretsPath is the directory holding the secrets mounted into deployed functions
+	SecretsPath string
+
+	// ResolverTTL is how long a resolved function endpoint is reused, zero disables the cache so
+	// every task inspects the function container, to resolve it and to read its cache policy
+	ResolverTTL time.Duration
+
+	// DeployFunctions enables the Deploy, Update and Delete RPCs, which need the CNI network
+	DeployFunctions bool
+
+	// ScaleFromZero starts the task of a function which is not running when it is invoked, it needs the CNI network
+	ScaleFromZero bool
+
+	// ReadyTimeout is how long a cold start gets, from scaling up to its watchdog becoming ready
+	ReadyTimeout time.Duration
+
+	// CachePolicy is the eviction policy of the result cache, see cache.Policies
+	CachePolicy string
+
+	// CacheSize is the capacity of the result cache in bytes
+	CacheSize int64
+
+	// CacheKeyHeaders are the request headers which are part of the cache key of every function
+	CacheKeyHeaders []string
+
+	// CacheKeyCanonicalJSON makes JSON request bodies part of the cache key in canonical form
+	CacheKeyCanonicalJSON bool
+
+	// CacheNegativeTTL is how long non-2xx function responses are cached, zero disables caching them
+	CacheNegativeTTL time.Duration
+
+	// DiskCachePath is the file of the persistent second tier of the result cache, empty disables it
+	DiskCachePath string
+
+	// DiskCacheSize is the capacity of the persistent second tier in bytes
+	DiskCacheSize int64
+
+	// CacheVerifyRate is the fraction of cache hits re-executed in the background to verify the cached result
+	CacheVerifyRate float64
+
+	// CacheVerifyThreshold is the divergence rate above which the results of a function stop being cached, zero disables it
+	CacheVerifyThreshold float64
+
+	// CacheVerifyMinSamples is the number of verifications needed before CacheVerifyThreshold applies
+	CacheVerifyMinSamples int
+
+	// CacheCompression is the algorithm cached results are compressed with, see compress.Algorithms
+	CacheCompression string
+
+	// CacheCompressionMinSize is the size in bytes from which cached results are compressed
+	CacheCompressionMinSize int64
+
+	// FileOrigins are the rules of the file caching proxy, [host][/path-prefix]=upstream, see origins.Parse
+	FileOrigins []string
+
+	// FileProxyAddress is the host, or host:port, functions reach the file caching proxy at, the rewritten
+	// task bodies point to it
+	FileProxyAddress string
+
+	// FileCachePath is the directory of the disk store of the file caching proxy, empty keeps files in memory only
+	FileCachePath string
+
+	// FileCacheSize is the capacity of the disk store of the file caching proxy in bytes
+	FileCacheSize int64
+
+	// FileCachePolicy is the eviction policy of the disk store of the file caching proxy, see cache.Policies
+	FileCachePolicy string
+
+	// FileCacheMemorySize is the capacity of the in-memory tier of the file caching proxy in bytes, zero disables it
+	FileCacheMemorySize int64
+
+	// FilePrefetchConcurrency is the number of input files prefetched at the same time
+	FilePrefetchConcurrency int
 }
 
 // ReadFromEnv loads the FaaSConfig and the Containerd specific config form the env variables
@@ -28,7 +102,41 @@ func ReadFromEnv(hasEnv types.HasEnv) (*types.FaaSConfig, *ProviderConfig, error
 	config.TCPPort = &port
 
 	providerConfig := &ProviderConfig{
-		Sock: types.ParseString(hasEnv.Getenv("sock"), "/run/containerd/containerd.sock"),
+		Sock:          types.ParseString(hasEnv.Getenv("sock"), "/run/containerd/containerd.sock"),
+		SecretsPath:   types.ParseString(hasEnv.Getenv("secrets_path"), "/var/lib/faasd-provider/secrets"),
+		ResolverTTL:   types.ParseIntOrDurationValue(hasEnv.Getenv("resolver_ttl"), time.Second*30),
+		ScaleFromZero: types.ParseBoolValue(hasEnv.Getenv("scale_from_zero"), false),
+
+		DeployFunctions: types.ParseBoolValue(hasEnv.Getenv("deploy_functions"), false),
+
+		ReadyTimeout:  types.ParseIntOrDurationValue(hasEnv.Getenv("ready_timeout"), time.Second*30),
+		CachePolicy:   types.ParseString(hasEnv.Getenv("cache_policy"), "lru"),
+		CacheSize:     types.ParseBytesValue(hasEnv.Getenv("cache_size"), 64*1024*1024),
+
+		CacheKeyHeaders:       types.ParseStringList(hasEnv.Getenv("cache_key_headers"), nil),
+		CacheKeyCanonicalJSON: types.ParseBoolValue(hasEnv.Getenv("cache_key_canonical_json"), false),
+		CacheNegativeTTL:      types.ParseIntOrDurationValue(hasEnv.Getenv("cache_negative_ttl"), 0),
+
+		DiskCachePath: hasEnv.Getenv("disk_cache_path"),
+		DiskCacheSize: types.ParseBytesValue(hasEnv.Getenv("disk_cache_size"), 1024*1024*1024),
+
+		CacheVerifyRate:       types.ParseFloatValue(hasEnv.Getenv("cache_verify_rate"), 0),
+		CacheVerifyThreshold:  types.ParseFloatValue(hasEnv.Getenv("cache_verify_threshold"), 0),
+		CacheVerifyMinSamples: types.ParseIntValue(hasEnv.Getenv("cache_verify_min_samples"), 20),
+
+		CacheCompression:        types.ParseString(hasEnv.Getenv("cache_compression"), "none"),
+		CacheCompressionMinSize: types.ParseBytesValue(hasEnv.Getenv("cache_compression_min_size"), 1024),
+
+		FileOrigins: types.ParseStringList(hasEnv.Getenv("file_origins"), []string{"mvatandoosts.ir/assets/images/=http://mvatandoosts.ir/assets/images/"}),
+
+		FileProxyAddress: types.ParseString(hasEnv.Getenv("file_proxy_address"), "192.168.43.220"),
+
+		FileCachePath:       hasEnv.Getenv("file_cache_path"),
+		FileCacheSize:       types.ParseBytesValue(hasEnv.Getenv("file_cache_size"), 10*1024*1024*1024),
+		FileCachePolicy:     types.ParseString(hasEnv.Getenv("file_cache_policy"), "lru"),
+		FileCacheMemorySize: types.ParseBytesValue(hasEnv.Getenv("file_cache_memory_size"), 64*1024*1024),
+
+		FilePrefetchConcurrency: types.ParseIntValue(hasEnv.Getenv("file_prefetch_concurrency"), 4),
 	}
 
 	return config, providerConfig, nil
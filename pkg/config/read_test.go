@@ -45,6 +45,90 @@ func Test_SetSockByEnv(t *testing.T) {
 	}
 }
 
+func Test_SetSecretsPathByEnv(t *testing.T) {
+	defaultPath := "/var/lib/faasd-provider/secrets"
+	expectedPath := "/non/default/secrets"
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.SecretsPath != defaultPath {
+		t.Fatalf("expected %q, got %q", defaultPath, config.SecretsPath)
+	}
+
+	env.Setenv("secrets_path", expectedPath)
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.SecretsPath != expectedPath {
+		t.Fatalf("expected %q, got %q", expectedPath, config.SecretsPath)
+	}
+}
+
+func Test_SetResolverTTL(t *testing.T) {
+	defaultTTL := "30s"
+
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.ResolverTTL.String() != defaultTTL {
+		t.Fatalf("expected %q, got %q", defaultTTL, config.ResolverTTL)
+	}
+
+	env.Setenv("resolver_ttl", "0")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.ResolverTTL != 0 {
+		t.Fatalf("expected resolver cache to be disabled, got %q", config.ResolverTTL)
+	}
+}
+
+func Test_SetScaleFromZero(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.ScaleFromZero {
+		t.Fatal("expected scale from zero to be disabled by default")
+	}
+
+	env.Setenv("scale_from_zero", "true")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !config.ScaleFromZero {
+		t.Fatal("expected scale from zero to be enabled")
+	}
+}
+
+func Test_SetDeployFunctions(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.DeployFunctions {
+		t.Fatal("expected deploying functions to be disabled by default")
+	}
+
+	env.Setenv("deploy_functions", "true")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !config.DeployFunctions {
+		t.Fatal("expected deploying functions to be enabled")
+	}
+}
+
 func Test_SetServiceTimeout(t *testing.T) {
 	defaultTimeout := "1m0s"
 
@@ -105,3 +189,136 @@ func Test_SetPort(t *testing.T) {
 		t.Fatalf("expected %d, got %d", newPort, config.TCPPort)
 	}
 }
+
+func Test_SetCacheSize(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.CacheSize != 64*1024*1024 {
+		t.Fatalf("expected %d, got %d", 64*1024*1024, config.CacheSize)
+	}
+
+	for value, expected := range map[string]int64{"1048576": 1048576, "16m": 16 * 1024 * 1024, "1GiB": 1024 * 1024 * 1024, "-1": 64 * 1024 * 1024} {
+		env.Setenv("cache_size", value)
+		_, config, err = ReadFromEnv(env)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if config.CacheSize != expected {
+			t.Fatalf("%q: expected %d, got %d", value, expected, config.CacheSize)
+		}
+	}
+}
+
+func Test_SetCacheKeyHeaders(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(config.CacheKeyHeaders) != 0 {
+		t.Fatalf("expected no headers, got %v", config.CacheKeyHeaders)
+	}
+
+	env.Setenv("cache_key_headers", "Accept, X-Tenant-Id,,")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(config.CacheKeyHeaders) != 2 || config.CacheKeyHeaders[0] != "Accept" || config.CacheKeyHeaders[1] != "X-Tenant-Id" {
+		t.Fatalf("expected [Accept X-Tenant-Id], got %v", config.CacheKeyHeaders)
+	}
+}
+
+func Test_SetCacheVerifyRate(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.CacheVerifyRate != 0 || config.CacheVerifyMinSamples != 20 {
+		t.Fatalf("expected verification to be disabled by default, got %f", config.CacheVerifyRate)
+	}
+
+	env.Setenv("cache_verify_rate", "0.05")
+	env.Setenv("cache_verify_threshold", "bad")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.CacheVerifyRate != 0.05 {
+		t.Fatalf("expected 0.05, got %f", config.CacheVerifyRate)
+	}
+	if config.CacheVerifyThreshold != 0 {
+		t.Fatalf("expected an invalid threshold to be ignored, got %f", config.CacheVerifyThreshold)
+	}
+}
+
+func Test_SetCacheCompression(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.CacheCompression != "none" || config.CacheCompressionMinSize != 1024 {
+		t.Fatalf("expected no compression from 1024 bytes by default, got %s from %d", config.CacheCompression, config.CacheCompressionMinSize)
+	}
+
+	env.Setenv("cache_compression", "zstd")
+	env.Setenv("cache_compression_min_size", "4KiB")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.CacheCompression != "zstd" || config.CacheCompressionMinSize != 4096 {
+		t.Fatalf("expected zstd from 4096 bytes, got %s from %d", config.CacheCompression, config.CacheCompressionMinSize)
+	}
+}
+
+func Test_SetFileOrigins(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(config.FileOrigins) != 1 || config.FileProxyAddress != "192.168.43.220" {
+		t.Fatalf("expected the default origin and proxy address, got %v and %q", config.FileOrigins, config.FileProxyAddress)
+	}
+
+	env.Setenv("file_origins", "/datasets/=http://storage:9000/, files.local=http://10.0.0.5/")
+	env.Setenv("file_proxy_address", "10.0.0.2:9090")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(config.FileOrigins) != 2 || config.FileOrigins[1] != "files.local=http://10.0.0.5/" {
+		t.Fatalf("expected 2 origins, got %v", config.FileOrigins)
+	}
+	if config.FileProxyAddress != "10.0.0.2:9090" {
+		t.Fatalf("expected 10.0.0.2:9090, got %q", config.FileProxyAddress)
+	}
+}
+
+func Test_SetFileCache(t *testing.T) {
+	env := NewEnvBucket()
+	_, config, err := ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.FileCachePath != "" || config.FileCacheMemorySize != 64*1024*1024 {
+		t.Fatalf("expected a memory only file cache of 64MiB by default, got %q and %d", config.FileCachePath, config.FileCacheMemorySize)
+	}
+
+	env.Setenv("file_cache_path", "/var/lib/faasd-agent/files")
+	env.Setenv("file_cache_size", "20GiB")
+	env.Setenv("file_cache_memory_size", "0")
+	_, config, err = ReadFromEnv(env)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if config.FileCacheSize != 20*1024*1024*1024 || config.FileCacheMemorySize != 0 {
+		t.Fatalf("expected 20GiB on disk without memory tier, got %d and %d", config.FileCacheSize, config.FileCacheMemorySize)
+	}
+}
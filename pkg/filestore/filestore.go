@@ -0,0 +1,411 @@
+// Package filestore keeps the input files of the file caching proxy on local
+// disk, bounded by their total size.
+//
+// Every file is written to a temporary file and renamed into place once
+// complete, and its index record is written to a bbolt database, so the
+// store is reloaded as it was after a restart and a crash never leaves a
+// partial file in it.
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"faasd-agent/pkg/cache"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var filesBucket = []byte("files")
+
+// openTimeout is how long Open waits for another process to release the index
+const openTimeout = 5 * time.Second
+
+// maxReplayedHits bounds the hits of a file replayed into the eviction
+// policy when the index is reloaded.
+const maxReplayedHits = 32
+
+// Origin describes the origin response a file was fetched with.
+type Origin struct {
+	ContentType  string
+	ETag         string
+	LastModified string
+	// ExpiresAt is when the file must be revalidated with the origin, zero
+	// means never
+	ExpiresAt time.Time
+}
+
+// Stale reports whether the file must be revalidated at now.
+func (o Origin) Stale(now time.Time) bool {
+	return !o.ExpiresAt.IsZero() && now.After(o.ExpiresAt)
+}
+
+// Meta describes a stored file.
+type Meta struct {
+	Key string
+	// File is the path of the content relative to the store directory
+	File       string
+	Size       int64
+	StoredAt   time.Time
+	AccessedAt time.Time
+	Hits       uint64
+	Origin
+}
+
+// Store is a content store of files on local disk bounded by their total
+// size, evicting files with one of the cache policies.
+type Store struct {
+	dir string
+	db  *bolt.DB
+
+	mutex sync.Mutex
+	// index holds the *Meta of every stored file, sized by the file size
+	index cache.Cache
+	// evicted collects the files evicted from index until they are deleted
+	evicted []*Meta
+}
+
+// Open opens, or creates, the store in dir with capacity bytes, evicting
+// with policy, one of cache.Policies. Files beyond capacity, when it was
+// lowered, are evicted.
+func Open(dir string, capacity int64, policy string) (*Store, error) {
+	s := &Store{dir: dir}
+	index, err := cache.New(policy, capacity, func(key string, value interface{}) {
+		s.evicted = append(s.evicted, value.(*Meta))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.index = index
+
+	for _, sub := range []string{"data", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("unable to create file store %s: %s", dir, err)
+		}
+	}
+	s.db, err = bolt.Open(filepath.Join(dir, "index.db"), 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file store index %s: %s", dir, err)
+	}
+	if err := s.load(); err != nil {
+		s.db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// load rebuilds the index from the database. Records without file, and files
+// without record, left by a crash are deleted.
+func (s *Store) load() error {
+	os.RemoveAll(filepath.Join(s.dir, "tmp"))
+	if err := os.MkdirAll(filepath.Join(s.dir, "tmp"), 0700); err != nil {
+		return err
+	}
+
+	var metas []*Meta
+	referenced := make(map[string]bool)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		files, err := tx.CreateBucketIfNotExists(filesBucket)
+		if err != nil {
+			return err
+		}
+
+		var orphans [][]byte
+		err = files.ForEach(func(k, v []byte) error {
+			meta := &Meta{}
+			if err := json.Unmarshal(v, meta); err != nil {
+				orphans = append(orphans, append([]byte{}, k...))
+				return nil
+			}
+			if info, err := os.Stat(filepath.Join(s.dir, meta.File)); err != nil || info.Size() != meta.Size {
+				orphans = append(orphans, append([]byte{}, k...))
+				return nil
+			}
+			metas = append(metas, meta)
+			referenced[meta.File] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range orphans {
+			if err := files.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to load file store index: %s", err)
+	}
+
+	err = filepath.Walk(filepath.Join(s.dir, "data"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		if rel, err := filepath.Rel(s.dir, path); err == nil && !referenced[rel] {
+			os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to scan file store: %s", err)
+	}
+
+	sort.Slice(metas, func(i, j int) bool { return metas[i].AccessedAt.Before(metas[j].AccessedAt) })
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, meta := range metas {
+		s.index.Add(meta.Key, meta, meta.Size)
+		for i := uint64(0); i < meta.Hits && i < maxReplayedHits; i++ {
+			s.index.Get(meta.Key)
+		}
+	}
+	return s.deleteEvicted()
+}
+
+// Open returns the content of key, which the caller must close, and records
+// the access.
+func (s *Store) Open(key string) (*os.File, Meta, bool) {
+	s.mutex.Lock()
+	value, found := s.index.Get(key)
+	if !found {
+		s.mutex.Unlock()
+		return nil, Meta{}, false
+	}
+	meta := value.(*Meta)
+	meta.AccessedAt = time.Now()
+	meta.Hits++
+	m := *meta
+	s.mutex.Unlock()
+
+	f, err := os.Open(filepath.Join(s.dir, m.File))
+	if err != nil {
+		s.Remove(key)
+		return nil, Meta{}, false
+	}
+	return f, m, true
+}
+
+// Peek returns the description of key without recording the access.
+func (s *Store) Peek(key string) (Meta, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	value, found := s.index.Peek(key)
+	if !found {
+		return Meta{}, false
+	}
+	return *value.(*Meta), true
+}
+
+// Revalidated replaces the origin of the file of key, after the origin
+// confirmed its content is unchanged.
+func (s *Store) Revalidated(key string, origin Origin) (Meta, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, found := s.index.Peek(key)
+	if !found {
+		return Meta{}, false
+	}
+	meta := value.(*Meta)
+	meta.Origin = origin
+	if err := s.put(meta); err != nil {
+		return Meta{}, false
+	}
+	return *meta, true
+}
+
+// Writer writes the content of a file to the store, it is stored once
+// committed.
+type Writer struct {
+	store  *Store
+	key    string
+	origin Origin
+	tmp    *os.File
+	size   int64
+}
+
+// Create starts writing the content of key, fetched with origin.
+func (s *Store) Create(key string, origin Origin) (*Writer, error) {
+	tmp, err := ioutil.TempFile(filepath.Join(s.dir, "tmp"), "file-")
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{store: s, key: key, origin: origin, tmp: tmp}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.tmp.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Abort discards what was written.
+func (w *Writer) Abort() {
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+}
+
+// Commit stores what was written as the content of the key, replacing its
+// previous content, and evicts other files when the capacity is exceeded.
+func (w *Writer) Commit() (Meta, error) {
+	s := w.store
+	if err := w.tmp.Sync(); err != nil {
+		w.Abort()
+		return Meta{}, err
+	}
+	if err := w.tmp.Close(); err != nil {
+		os.Remove(w.tmp.Name())
+		return Meta{}, err
+	}
+	if w.size > s.index.Capacity() {
+		os.Remove(w.tmp.Name())
+		return Meta{}, fmt.Errorf("file of %d bytes exceeds the file store capacity", w.size)
+	}
+
+	now := time.Now()
+	sum := sha256.Sum256([]byte(w.key))
+	name := hex.EncodeToString(sum[:])
+	meta := &Meta{
+		Key: w.key,
+		// a new name per version, so an eviction of the previous version
+		// never deletes this one
+		File:       filepath.Join("data", name[:2], fmt.Sprintf("%s-%d", name, now.UnixNano())),
+		Size:       w.size,
+		StoredAt:   now,
+		AccessedAt: now,
+		Origin:     w.origin,
+	}
+	if err := os.MkdirAll(filepath.Join(s.dir, filepath.Dir(meta.File)), 0700); err != nil {
+		os.Remove(w.tmp.Name())
+		return Meta{}, err
+	}
+	if err := os.Rename(w.tmp.Name(), filepath.Join(s.dir, meta.File)); err != nil {
+		os.Remove(w.tmp.Name())
+		return Meta{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.put(meta); err != nil {
+		os.Remove(filepath.Join(s.dir, meta.File))
+		return Meta{}, fmt.Errorf("unable to index %s: %s", w.key, err)
+	}
+	if previous, found := s.index.Peek(w.key); found {
+		s.evicted = append(s.evicted, previous.(*Meta))
+	}
+	if !s.index.Add(w.key, meta, meta.Size) {
+		s.evicted = append(s.evicted, meta)
+	}
+	if err := s.deleteEvicted(); err != nil {
+		return Meta{}, err
+	}
+	return *meta, nil
+}
+
+func (s *Store) put(meta *Meta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(filesBucket).Put([]byte(meta.Key), data)
+	})
+}
+
+// deleteEvicted deletes the files evicted from the index, and their records
+// unless the key was stored again. The caller must hold the mutex.
+func (s *Store) deleteEvicted() error {
+	if len(s.evicted) == 0 {
+		return nil
+	}
+	evicted := s.evicted
+	s.evicted = nil
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, meta := range evicted {
+			if current, found := s.index.Peek(meta.Key); found && current.(*Meta).File != meta.File {
+				continue
+			}
+			if err := tx.Bucket(filesBucket).Delete([]byte(meta.Key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	for _, meta := range evicted {
+		os.Remove(filepath.Join(s.dir, meta.File))
+	}
+	return err
+}
+
+// Remove removes the file of key.
+func (s *Store) Remove(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, found := s.index.Peek(key)
+	if !found {
+		return false
+	}
+	s.index.Remove(key)
+	s.evicted = append(s.evicted, value.(*Meta))
+	return s.deleteEvicted() == nil
+}
+
+// Keys returns the stored keys.
+func (s *Store) Keys() []string {
+	return s.index.Keys()
+}
+
+// Len returns the number of stored files.
+func (s *Store) Len() int {
+	return s.index.Len()
+}
+
+// Size returns the total size of the stored files in bytes.
+func (s *Store) Size() int64 {
+	return s.index.Size()
+}
+
+// Capacity returns the maximum total size of the stored files in bytes.
+func (s *Store) Capacity() int64 {
+	return s.index.Capacity()
+}
+
+// Close saves the accesses of the files, so the eviction order survives a
+// restart, and closes the index.
+func (s *Store) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, key := range s.index.Keys() {
+			value, found := s.index.Peek(key)
+			if !found {
+				continue
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(filesBucket).Put([]byte(key), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package filestore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"faasd-agent/pkg/cache"
+)
+
+func put(t *testing.T, s *Store, key string, content []byte) {
+	w, err := s.Create(key, Origin{ContentType: "image/jpeg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func read(t *testing.T, s *Store, key string) ([]byte, bool) {
+	f, _, found := s.Open(key)
+	if !found {
+		return nil, false
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content, true
+}
+
+func Test_StoreEvictsAndReloads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir, 10, cache.PolicyLRU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put(t, s, "a", []byte("aaaa"))
+	put(t, s, "b", []byte("bbbb"))
+	read(t, s, "a")
+	put(t, s, "c", []byte("cccc"))
+	if _, found := s.Peek("b"); found {
+		t.Fatalf("expected the least recently used file to be evicted")
+	}
+	put(t, s, "a", []byte("AAAAA"))
+	if s.Size() != 9 || s.Len() != 2 {
+		t.Fatalf("expected 2 files of 9 bytes, got %d of %d bytes", s.Len(), s.Size())
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(dir, 10, cache.PolicyLRU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if content, found := read(t, s, "a"); !found || !bytes.Equal(content, []byte("AAAAA")) {
+		t.Fatalf("expected AAAAA after a restart, got %q", content)
+	}
+	if meta, _ := s.Peek("a"); meta.ContentType != "image/jpeg" {
+		t.Fatalf("expected the origin of the file to be reloaded, got %+v", meta.Origin)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "data", "*", "*"))
+	if len(files) != 2 {
+		t.Fatalf("expected the files of evicted and replaced keys to be deleted, got %v", files)
+	}
+}
+
+func Test_StoreAbortAndOversized(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir, 4, cache.PolicyLFU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	w, _ := s.Create("a", Origin{})
+	w.Write([]byte("aa"))
+	w.Abort()
+	if _, found := s.Peek("a"); found {
+		t.Fatalf("expected an aborted file not to be stored")
+	}
+
+	w, _ = s.Create("b", Origin{})
+	w.Write([]byte("too large"))
+	if _, err := w.Commit(); err == nil {
+		t.Fatalf("expected an error for a file larger than the capacity")
+	}
+	if tmp, _ := ioutil.ReadDir(filepath.Join(dir, "tmp")); len(tmp) != 0 {
+		t.Fatalf("expected no temporary file left, got %d", len(tmp))
+	}
+}
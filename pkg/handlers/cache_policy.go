@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"faasd-agent/pkg/types"
+)
+
+// Annotations controlling how the results of a function are cached.
+const (
+	cacheEnabledAnnotation         = "cache.enabled"
+	cacheTTLAnnotation             = "cache.ttl"
+	cacheMaxEntryBytesAnnotation   = "cache.max-entry-bytes"
+	cacheKeyHeadersAnnotation      = "cache.key-headers"
+	cacheStaleRevalidateAnnotation = "cache.stale-while-revalidate"
+	cacheStaleIfErrorAnnotation    = "cache.stale-if-error"
+)
+
+// CachePolicy is how the results of a function are cached, set by its annotations.
+type CachePolicy struct {
+	// Enabled is false for functions whose results must not be cached, such
+	// as non-deterministic ones
+	Enabled bool
+	// TTL is how long a result is served from the cache, zero means until evicted
+	TTL time.Duration
+	// MaxEntryBytes is the size of the largest result cached, zero means no limit
+	MaxEntryBytes int64
+	// KeyHeaders are the canonical names of the request headers which are
+	// part of the cache key
+	KeyHeaders []string
+	// StaleWhileRevalidate is how long after it expires a result is still
+	// served while it is refreshed in the background
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is how long after it expires a result is served when the
+	// function fails
+	StaleIfError time.Duration
+}
+
+// StaleWindow is how long after it expires a result may still be served.
+func (p CachePolicy) StaleWindow() time.Duration {
+	if p.StaleWhileRevalidate > p.StaleIfError {
+		return p.StaleWhileRevalidate
+	}
+	return p.StaleIfError
+}
+
+// DefaultCachePolicy caches every result until it is evicted.
+var DefaultCachePolicy = CachePolicy{Enabled: true}
+
+// CachePolicy returns the cache policy set by the annotations of the function.
+func (f Function) CachePolicy() CachePolicy {
+	return cachePolicy(f.annotations)
+}
+
+func cachePolicy(annotations map[string]string) CachePolicy {
+	policy := CachePolicy{
+		Enabled:       types.ParseBoolValue(annotations[cacheEnabledAnnotation], DefaultCachePolicy.Enabled),
+		TTL:           types.ParseIntOrDurationValue(annotations[cacheTTLAnnotation], DefaultCachePolicy.TTL),
+		MaxEntryBytes: types.ParseBytesValue(annotations[cacheMaxEntryBytesAnnotation], DefaultCachePolicy.MaxEntryBytes),
+
+		StaleWhileRevalidate: types.ParseIntOrDurationValue(annotations[cacheStaleRevalidateAnnotation], DefaultCachePolicy.StaleWhileRevalidate),
+		StaleIfError:         types.ParseIntOrDurationValue(annotations[cacheStaleIfErrorAnnotation], DefaultCachePolicy.StaleIfError),
+	}
+
+	for _, header := range strings.Split(annotations[cacheKeyHeadersAnnotation], ",") {
+		if header = strings.TrimSpace(header); len(header) > 0 {
+			policy.KeyHeaders = append(policy.KeyHeaders, http.CanonicalHeaderKey(header))
+		}
+	}
+	return policy
+}
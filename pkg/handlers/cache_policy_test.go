@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_CachePolicyDefaults(t *testing.T) {
+	policy := Function{}.CachePolicy()
+	if !reflect.DeepEqual(policy, DefaultCachePolicy) {
+		t.Fatalf("expected %+v, got %+v", DefaultCachePolicy, policy)
+	}
+}
+
+func Test_CachePolicyFromAnnotations(t *testing.T) {
+	f := Function{annotations: map[string]string{
+		"cache.enabled":         "false",
+		"cache.ttl":             "90s",
+		"cache.max-entry-bytes": "1m",
+		"cache.key-headers":     "accept, x-tenant-id,",
+	}}
+
+	expected := CachePolicy{
+		Enabled:       false,
+		TTL:           90 * time.Second,
+		MaxEntryBytes: 1024 * 1024,
+		KeyHeaders:    []string{"Accept", "X-Tenant-Id"},
+	}
+	if policy := f.CachePolicy(); !reflect.DeepEqual(policy, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, policy)
+	}
+}
+
+func Test_CachePolicyIgnoresInvalidValues(t *testing.T) {
+	f := Function{annotations: map[string]string{
+		"cache.ttl":             "soon",
+		"cache.max-entry-bytes": "large",
+	}}
+
+	if policy := f.CachePolicy(); !reflect.DeepEqual(policy, DefaultCachePolicy) {
+		t.Fatalf("expected %+v, got %+v", DefaultCachePolicy, policy)
+	}
+}
+
+func Test_CachePolicyStaleWindows(t *testing.T) {
+	f := Function{annotations: map[string]string{
+		"cache.ttl":                    "1m",
+		"cache.stale-while-revalidate": "30s",
+		"cache.stale-if-error":         "10m",
+	}}
+
+	policy := f.CachePolicy()
+	if policy.StaleWhileRevalidate != 30*time.Second || policy.StaleIfError != 10*time.Minute {
+		t.Fatalf("expected 30s and 10m, got %s and %s", policy.StaleWhileRevalidate, policy.StaleIfError)
+	}
+	if window := policy.StaleWindow(); window != 10*time.Minute {
+		t.Fatalf("expected a stale window of 10m, got %s", window)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"faasd-agent/pkg/cninetwork"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	gocni "github.com/containerd/go-cni"
+	"golang.org/x/sys/unix"
+)
+
+// killTimeout is how long a task gets to exit after SIGTERM before it is killed
+const killTimeout = 30 * time.Second
+
+// Delete removes the function from the CNI network and deletes its task,
+// container and snapshot.
+func Delete(ctx context.Context, client *containerd.Client, cni gocni.CNI, name string) error {
+	function, err := GetFunction(client, name)
+	if err != nil {
+		return fmt.Errorf("function %s not found: %s", name, err)
+	}
+	ctx = namespaces.WithNamespace(ctx, FunctionNamespace)
+
+	if function.replicas != 0 {
+		if err := cninetwork.DeleteCNINetwork(ctx, cni, client, name); err != nil {
+			log.Printf("[Delete] error removing CNI network for %s, %s\n", name, err)
+		}
+	}
+
+	return removeContainer(ctx, client, name)
+}
+
+// removeContainer kills the task of the container, if any, and deletes the container with its snapshot.
+func removeContainer(ctx context.Context, client *containerd.Client, name string) error {
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		return fmt.Errorf("unable to load container %s: %s", name, err)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("unable to get task %s: %s", name, err)
+	}
+	if err == nil {
+		if err := killTask(ctx, task); err != nil {
+			return fmt.Errorf("error killing task %s, %s", name, err)
+		}
+	}
+
+	if err := container.Delete(ctx, containerd.WithSnapshotCleanup); err != nil {
+		return fmt.Errorf("error deleting container %s, %s", name, err)
+	}
+	return nil
+}
+
+// killTask sends SIGTERM to the task, then SIGKILL after killTimeout, and deletes it.
+func killTask(ctx context.Context, task containerd.Task) error {
+	wait, err := task.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Kill(ctx, unix.SIGTERM, containerd.WithKillAll); err != nil && !errdefs.IsNotFound(err) {
+		log.Printf("[Delete] error killing task %s: %s", task.ID(), err)
+	}
+
+	select {
+	case <-wait:
+	case <-time.After(killTimeout):
+		if err := task.Kill(ctx, unix.SIGKILL, containerd.WithKillAll); err != nil {
+			log.Printf("[Delete] error force killing task %s: %s", task.ID(), err)
+		}
+		<-wait
+	}
+
+	_, err = task.Delete(ctx)
+	return err
+}
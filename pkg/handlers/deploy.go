@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"faasd-agent/pkg/cninetwork"
+	"faasd-agent/pkg/types"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	"github.com/containerd/containerd/reference/docker"
+	"github.com/containerd/containerd/remotes"
+	dockerremote "github.com/containerd/containerd/remotes/docker"
+	gocni "github.com/containerd/go-cni"
+	units "github.com/docker/go-units"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// secretsMountDir is where secrets are mounted inside the function container
+	secretsMountDir = "/var/openfaas/secrets"
+
+	// cpuPeriod is the CFS period used to apply CPU limits, in microseconds
+	cpuPeriod = 100000
+)
+
+// Deploy pulls the image of the function, creates its container and task in
+// FunctionNamespace and attaches the task to the CNI network.
+func Deploy(ctx context.Context, client *containerd.Client, cni gocni.CNI, req types.FunctionDeployment, secretMountPath string) error {
+	if err := validateDeployment(req); err != nil {
+		return err
+	}
+	ctx = namespaces.WithNamespace(ctx, FunctionNamespace)
+
+	image, err := prepull(ctx, client, req)
+	if err != nil {
+		return err
+	}
+
+	name := req.Service
+	labels, err := buildLabels(&req)
+	if err != nil {
+		return fmt.Errorf("unable to apply labels to container: %s, error: %s", name, err)
+	}
+
+	specOpts, err := buildSpecOpts(image, req, secretMountPath)
+	if err != nil {
+		return err
+	}
+
+	container, err := client.NewContainer(
+		ctx,
+		name,
+		containerd.WithImage(image),
+		containerd.WithSnapshotter(snapshotter()),
+		containerd.WithNewSnapshot(name+"-snapshot", image),
+		containerd.WithNewSpec(specOpts...),
+		containerd.WithContainerLabels(labels),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create container: %s, error: %s", name, err)
+	}
+
+	if err := createTask(ctx, container, cni); err != nil {
+		// a container left behind would make every retry fail with "already exists"
+		if deleteErr := container.Delete(cleanupContext(), containerd.WithSnapshotCleanup); deleteErr != nil {
+			log.Printf("[Deploy] unable to delete container %s after failing to start it: %s\n", name, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// cleanupContext returns the context undoing a failed deployment, it is not
+// cancelled with the request which failed.
+func cleanupContext() context.Context {
+	return namespaces.WithNamespace(context.Background(), FunctionNamespace)
+}
+
+func validateDeployment(req types.FunctionDeployment) error {
+	if len(req.Service) == 0 {
+		return fmt.Errorf("service is required")
+	}
+	if len(req.Image) == 0 {
+		return fmt.Errorf("image is required for service: %s", req.Service)
+	}
+	if len(req.Namespace) > 0 && req.Namespace != FunctionNamespace {
+		return fmt.Errorf("namespace %s is not supported, use %s", req.Namespace, FunctionNamespace)
+	}
+	return nil
+}
+
+// prepull makes sure the image of the function is available and unpacked.
+func prepull(ctx context.Context, client *containerd.Client, req types.FunctionDeployment) (containerd.Image, error) {
+	start := time.Now()
+	ref, err := docker.ParseDockerRef(req.Image)
+	if err != nil {
+		return nil, err
+	}
+	imageName := ref.String()
+
+	image, err := client.GetImage(ctx, imageName)
+	if err != nil || faasServicesPullAlways {
+		if err != nil && !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+
+		resolver, err := newResolver(req.RegistryAuth)
+		if err != nil {
+			return nil, err
+		}
+		image, err = client.Pull(ctx, imageName, containerd.WithPullUnpack, containerd.WithResolver(resolver))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to pull image %s", imageName)
+		}
+	}
+
+	unpacked, err := image.IsUnpacked(ctx, snapshotter())
+	if err != nil {
+		return nil, fmt.Errorf("cannot check if unpacked: %s", err)
+	}
+	if !unpacked {
+		if err := image.Unpack(ctx, snapshotter()); err != nil {
+			return nil, fmt.Errorf("cannot unpack: %s", err)
+		}
+	}
+
+	size, _ := image.Size(ctx)
+	log.Printf("Image for: %s size: %d, took: %fs\n", image.Name(), size, time.Since(start).Seconds())
+
+	return image, nil
+}
+
+// newResolver returns a registry resolver using registryAuth, the base64
+// encoded "username:password" used by Docker, when it is set.
+func newResolver(registryAuth string) (remotes.Resolver, error) {
+	options := dockerremote.ResolverOptions{}
+	if len(registryAuth) > 0 {
+		decoded, err := base64.StdEncoding.DecodeString(registryAuth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry auth: %s", err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid registry auth, expected username:password")
+		}
+		options.Credentials = func(string) (string, string, error) {
+			return parts[0], parts[1], nil
+		}
+	}
+	return dockerremote.NewResolver(options), nil
+}
+
+func buildSpecOpts(image containerd.Image, req types.FunctionDeployment, secretMountPath string) ([]oci.SpecOpts, error) {
+	mounts := getMounts()
+	for _, secret := range req.Secrets {
+		mounts = append(mounts, specs.Mount{
+			Destination: path.Join(secretsMountDir, secret),
+			Type:        "bind",
+			Source:      path.Join(secretMountPath, secret),
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+
+	opts := []oci.SpecOpts{
+		oci.WithImageConfig(image),
+		oci.WithHostname(req.Service),
+		oci.WithCapabilities([]string{"CAP_NET_RAW"}),
+		oci.WithMounts(mounts),
+		oci.WithEnv(prepareEnv(req.EnvProcess, req.EnvVars)),
+	}
+
+	if req.ReadOnlyRootFilesystem {
+		opts = append(opts, oci.WithRootFSReadonly())
+	}
+
+	if req.Limits != nil && len(req.Limits.Memory) > 0 {
+		limit, err := units.RAMInBytes(req.Limits.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory limit %q: %s", req.Limits.Memory, err)
+		}
+		opts = append(opts, oci.WithMemoryLimit(uint64(limit)))
+	}
+
+	if req.Limits != nil && len(req.Limits.CPU) > 0 {
+		cpus, err := parseCPU(req.Limits.CPU)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, oci.WithCPUCFS(int64(cpus*cpuPeriod), cpuPeriod))
+	}
+
+	return opts, nil
+}
+
+// parseCPU parses a CPU quantity given in cores ("0.5") or millicores ("500m").
+func parseCPU(cpu string) (float64, error) {
+	value := cpu
+	scale := 1.0
+	if strings.HasSuffix(cpu, "m") {
+		value = strings.TrimSuffix(cpu, "m")
+		scale = 1000
+	}
+
+	cpus, err := strconv.ParseFloat(value, 64)
+	if err != nil || cpus <= 0 {
+		return 0, fmt.Errorf("invalid cpu limit %q", cpu)
+	}
+	return cpus / scale, nil
+}
+
+func buildLabels(request *types.FunctionDeployment) (map[string]string, error) {
+	labels := map[string]string{}
+
+	if request.Labels != nil {
+		for k, v := range *request.Labels {
+			labels[k] = v
+		}
+	}
+
+	if request.Annotations != nil {
+		for k, v := range *request.Annotations {
+			key := fmt.Sprintf("%s%s", annotationLabelPrefix, k)
+			if _, ok := labels[key]; ok {
+				return nil, fmt.Errorf("key %s cannot be used as a label due to a conflict with annotation prefix %s", k, annotationLabelPrefix)
+			}
+			labels[key] = v
+		}
+	}
+
+	return labels, nil
+}
+
+// createTask creates and starts the task of container and attaches it to the
+// CNI network. The task is detached and deleted when it cannot be started.
+func createTask(ctx context.Context, container containerd.Container, cni gocni.CNI) (err error) {
+	name := container.ID()
+
+	task, taskErr := container.NewTask(ctx, cio.NullIO)
+	if taskErr != nil {
+		return fmt.Errorf("unable to start task: %s, error: %s", name, taskErr)
+	}
+
+	log.Printf("Container ID: %s\tTask ID %s:\tTask PID: %d\t\n", name, task.ID(), task.Pid())
+
+	defer func() {
+		if err != nil {
+			deleteFailedTask(cni, task)
+		}
+	}()
+
+	network, err := cninetwork.CreateCNINetwork(ctx, cni, task, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	ip, err := cninetwork.GetIPAddress(network, task)
+	if err != nil {
+		return err
+	}
+	log.Printf("%s has IP: %s.\n", name, ip.String())
+
+	if _, err := task.Wait(ctx); err != nil {
+		return errors.Wrapf(err, "Unable to wait for task to start: %s", name)
+	}
+
+	if err := task.Start(ctx); err != nil {
+		return errors.Wrapf(err, "Unable to start task: %s", name)
+	}
+	return nil
+}
+
+// deleteFailedTask detaches task from the CNI network, if it was attached,
+// and kills and deletes it.
+func deleteFailedTask(cni gocni.CNI, task containerd.Task) {
+	ctx := cleanupContext()
+	if err := cninetwork.RemoveCNINetwork(ctx, cni, task); err != nil {
+		log.Printf("[Deploy] unable to remove CNI network of failed task %s: %s\n", task.ID(), err)
+	}
+	if _, err := task.Delete(ctx, containerd.WithProcessKill); err != nil && !errdefs.IsNotFound(err) {
+		log.Printf("[Deploy] unable to delete failed task %s: %s\n", task.ID(), err)
+	}
+}
+
+func prepareEnv(envProcess string, reqEnvVars map[string]string) []string {
+	envs := []string{}
+	fprocessFound := false
+	fprocess := "fprocess=" + envProcess
+	if len(envProcess) > 0 {
+		fprocessFound = true
+	}
+
+	for k, v := range reqEnvVars {
+		if k == "fprocess" {
+			fprocessFound = true
+			fprocess = "fprocess=" + v
+		} else {
+			envs = append(envs, k+"="+v)
+		}
+	}
+	if fprocessFound {
+		envs = append(envs, fprocess)
+	}
+	return envs
+}
+
+// getMounts returns mounts for the resolv.conf and hosts files of the working
+// directory, when they exist, so functions resolve names like faasd services.
+func getMounts() []specs.Mount {
+	wd, _ := os.Getwd()
+	mounts := []specs.Mount{}
+	for _, file := range []string{"resolv.conf", "hosts"} {
+		source := path.Join(wd, file)
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+		mounts = append(mounts, specs.Mount{
+			Destination: path.Join("/etc", file),
+			Type:        "bind",
+			Source:      source,
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+	return mounts
+}
+
+func snapshotter() string {
+	if val, ok := os.LookupEnv("snapshotter"); ok {
+		return val
+	}
+	return defaultSnapshotter
+}
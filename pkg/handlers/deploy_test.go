@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"testing"
+
+	"faasd-agent/pkg/types"
+)
+
+func Test_BuildLabelsPrefixesAnnotations(t *testing.T) {
+	labels := map[string]string{"com.openfaas.scale.min": "1"}
+	annotations := map[string]string{"topic": "faas"}
+	req := types.FunctionDeployment{Labels: &labels, Annotations: &annotations}
+
+	got, err := buildLabels(&req)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if got["com.openfaas.scale.min"] != "1" {
+		t.Fatalf("expected label to be kept, got %v", got)
+	}
+	if got[annotationLabelPrefix+"topic"] != "faas" {
+		t.Fatalf("expected prefixed annotation, got %v", got)
+	}
+}
+
+func Test_BuildLabelsAnnotationConflict(t *testing.T) {
+	labels := map[string]string{annotationLabelPrefix + "topic": "label"}
+	annotations := map[string]string{"topic": "annotation"}
+	req := types.FunctionDeployment{Labels: &labels, Annotations: &annotations}
+
+	if _, err := buildLabels(&req); err == nil {
+		t.Fatal("expected an error for a conflicting annotation")
+	}
+}
+
+func Test_ParseCPU(t *testing.T) {
+	cases := map[string]float64{"1": 1, "0.5": 0.5, "250m": 0.25}
+	for value, expected := range cases {
+		got, err := parseCPU(value)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", value, err)
+		}
+		if got != expected {
+			t.Fatalf("expected %v for %q, got %v", expected, value, got)
+		}
+	}
+
+	if _, err := parseCPU("lots"); err == nil {
+		t.Fatal("expected an error for an invalid cpu value")
+	}
+}
@@ -41,6 +41,11 @@ type Function struct {
 	CloseChannel  chan struct{}
 }
 
+// Image returns the image the function runs.
+func (f Function) Image() string {
+	return f.image
+}
+
 // ListFunctions returns a map of all functions with running tasks on namespace
 func ListFunctions(client *containerd.Client) (map[string]*Function, error) {
 	ctx := namespaces.WithNamespace(context.Background(), FunctionNamespace)
@@ -1,31 +1,89 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
-	// "log"
+	"log"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/containerd/containerd"
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/events"
+	"github.com/containerd/containerd/namespaces"
+	gocni "github.com/containerd/go-cni"
+	"github.com/containerd/typeurl"
+	"golang.org/x/sync/singleflight"
 )
 
-const watchdogPort = 8080
+const (
+	watchdogPort = 8080
 
+	// watchRetryDelay is how long Watch waits before subscribing again after the event stream fails
+	watchRetryDelay = time.Second
+)
+
+// endpoint is a resolved function together with the time it was resolved
+type endpoint struct {
+	url        url.URL
+	function   Function
+	resolvedAt time.Time
+}
+
+// InvokeResolver resolves function names to the URL of their watchdog. When
+// created with a TTL it keeps an endpoint table, kept fresh by Watch, so the
+// container does not have to be inspected on every request.
 type InvokeResolver struct {
 	client *containerd.Client
+	ttl    time.Duration
+
+	mutex     sync.RWMutex
+	endpoints map[string]endpoint
+
+	// scale from zero, enabled when cni is set
+	cni          gocni.CNI
+	readyTimeout time.Duration
+	onColdStart  func(functionName string, d time.Duration)
+	coldStarts   singleflight.Group
 }
 
+// NewInvokeResolver creates a resolver which inspects the function container on every call.
 func NewInvokeResolver(client *containerd.Client) *InvokeResolver {
-	return &InvokeResolver{client: client}
+	return NewCachedInvokeResolver(client, 0)
+}
+
+// NewCachedInvokeResolver creates a resolver which caches the endpoints of
+// running functions for up to ttl, a ttl of zero disables the cache.
+func NewCachedInvokeResolver(client *containerd.Client, ttl time.Duration) *InvokeResolver {
+	return &InvokeResolver{client: client, ttl: ttl, endpoints: make(map[string]endpoint)}
 }
 
 func (i *InvokeResolver) Resolve(functionName string) (url.URL, Function, error) {
-	// log.Printf("Function handler Resolve: %q\n", functionName)
+	if i.ttl > 0 {
+		i.mutex.RLock()
+		e, found := i.endpoints[functionName]
+		i.mutex.RUnlock()
+		if found && time.Since(e.resolvedAt) < i.ttl {
+			return e.url, e.function, nil
+		}
+	}
 
 	function, err := GetFunction(i.client, functionName)
 	if err != nil {
 		return url.URL{}, Function{}, fmt.Errorf("%s not found", functionName)
 	}
 
+	if len(function.IP) == 0 {
+		if i.cni == nil {
+			return url.URL{}, Function{}, fmt.Errorf("%s is not running", functionName)
+		}
+		function, err = i.coldStart(functionName)
+		if err != nil {
+			return url.URL{}, Function{}, err
+		}
+	}
+
 	serviceIP := function.IP
 
 	urlStr := fmt.Sprintf("http://%s:%d", serviceIP, watchdogPort)
@@ -35,5 +93,168 @@ func (i *InvokeResolver) Resolve(functionName string) (url.URL, Function, error)
 		return url.URL{}, Function{}, err
 	}
 
+	if i.ttl > 0 {
+		i.mutex.Lock()
+		i.endpoints[functionName] = endpoint{url: *urlRes, function: function, resolvedAt: time.Now()}
+		i.mutex.Unlock()
+	}
+
 	return *urlRes, function, nil
 }
+
+// Lookup returns the function, from the endpoint table when it is resolved,
+// without starting it when it is not running. With a ttl of zero there is no
+// endpoint table, every call inspects the container.
+func (i *InvokeResolver) Lookup(functionName string) (Function, error) {
+	if i.ttl > 0 {
+		i.mutex.RLock()
+		e, found := i.endpoints[functionName]
+		i.mutex.RUnlock()
+		if found && time.Since(e.resolvedAt) < i.ttl {
+			return e.function, nil
+		}
+	}
+
+	function, err := GetFunction(i.client, functionName)
+	if err != nil {
+		return Function{}, fmt.Errorf("%s not found", functionName)
+	}
+	return function, nil
+}
+
+// EnableScaleFromZero makes Resolve start the task of functions which are not
+// running and wait for their watchdog, within readyTimeout for the whole cold
+// start. Concurrent callers share a single cold start, whose duration is
+// passed to onColdStart.
+func (i *InvokeResolver) EnableScaleFromZero(cni gocni.CNI, readyTimeout time.Duration, onColdStart func(functionName string, d time.Duration)) {
+	i.cni = cni
+	i.readyTimeout = readyTimeout
+	i.onColdStart = onColdStart
+}
+
+// Invalidate drops the cached endpoint of the function.
+func (i *InvokeResolver) Invalidate(functionName string) {
+	i.mutex.Lock()
+	delete(i.endpoints, functionName)
+	i.mutex.Unlock()
+}
+
+// InvalidateAll drops every cached endpoint.
+func (i *InvokeResolver) InvalidateAll() {
+	i.mutex.Lock()
+	i.endpoints = make(map[string]endpoint)
+	i.mutex.Unlock()
+}
+
+// coldStart starts the task of the function and waits for its watchdog, callers
+// for the same function wait on the cold start already in progress.
+func (i *InvokeResolver) coldStart(functionName string) (Function, error) {
+	v, err, _ := i.coldStarts.Do(functionName, func() (interface{}, error) {
+		start := time.Now()
+		// a hung scale up must not block the callers waiting on it forever
+		ctx, cancel := context.WithTimeout(context.Background(), i.readyTimeout)
+		defer cancel()
+		if err := ScaleUp(ctx, i.client, i.cni, functionName); err != nil {
+			return nil, fmt.Errorf("unable to scale up %s: %s", functionName, err)
+		}
+
+		function, err := GetFunction(i.client, functionName)
+		if err != nil {
+			return nil, err
+		}
+		if len(function.IP) == 0 {
+			return nil, fmt.Errorf("%s has no IP address after scaling up", functionName)
+		}
+
+		if err := WaitForReady(ctx, function.IP); err != nil {
+			return nil, err
+		}
+
+		d := time.Since(start)
+		log.Printf("[Scale] cold start of %s took %fs\n", functionName, d.Seconds())
+		if i.onColdStart != nil {
+			i.onColdStart(functionName, d)
+		}
+		return function, nil
+	})
+	if err != nil {
+		return Function{}, err
+	}
+	return v.(Function), nil
+}
+
+// Watch subscribes to the task and container events of FunctionNamespace and
+// invalidates the endpoints of the functions they concern. It blocks until
+// ctx is done, subscribing again whenever the event stream fails.
+func (i *InvokeResolver) Watch(ctx context.Context) {
+	ctx = namespaces.WithNamespace(ctx, FunctionNamespace)
+	for {
+		err := i.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// events may have been missed while disconnected
+		i.InvalidateAll()
+		log.Printf("resolver event stream failed, retrying: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+func (i *InvokeResolver) watch(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	envelopes, errs := i.client.Subscribe(ctx,
+		fmt.Sprintf(`namespace==%s,topic~="^/tasks/"`, FunctionNamespace),
+		fmt.Sprintf(`namespace==%s,topic~="^/containers/"`, FunctionNamespace),
+	)
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case envelope, ok := <-envelopes:
+			if !ok {
+				return fmt.Errorf("event stream closed")
+			}
+			i.handleEvent(envelope)
+		}
+	}
+}
+
+// handleEvent invalidates the endpoint of the function the event concerns.
+func (i *InvokeResolver) handleEvent(envelope *events.Envelope) {
+	event, err := typeurl.UnmarshalAny(envelope.Event)
+	if err != nil {
+		log.Printf("cannot decode event %s: %v", envelope.Topic, err)
+		return
+	}
+	if name := eventContainerID(event); len(name) > 0 {
+		i.Invalidate(name)
+	}
+}
+
+// eventContainerID returns the container an event changes the endpoint of, if any.
+func eventContainerID(event interface{}) string {
+	switch e := event.(type) {
+	case *eventstypes.TaskStart:
+		return e.ContainerID
+	case *eventstypes.TaskExit:
+		return e.ContainerID
+	case *eventstypes.TaskDelete:
+		return e.ContainerID
+	case *eventstypes.TaskPaused:
+		return e.ContainerID
+	case *eventstypes.TaskOOM:
+		return e.ContainerID
+	case *eventstypes.ContainerUpdate:
+		return e.ID
+	case *eventstypes.ContainerDelete:
+		return e.ID
+	}
+	return ""
+}
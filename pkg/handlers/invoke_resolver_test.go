@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/events"
+	"github.com/containerd/typeurl"
+)
+
+func Test_EventContainerID(t *testing.T) {
+	scenarios := []struct {
+		event interface{}
+		id    string
+	}{
+		{event: &eventstypes.TaskStart{ContainerID: "fn"}, id: "fn"},
+		{event: &eventstypes.TaskExit{ContainerID: "fn"}, id: "fn"},
+		{event: &eventstypes.TaskDelete{ContainerID: "fn"}, id: "fn"},
+		{event: &eventstypes.TaskPaused{ContainerID: "fn"}, id: "fn"},
+		{event: &eventstypes.TaskOOM{ContainerID: "fn"}, id: "fn"},
+		{event: &eventstypes.ContainerUpdate{ID: "fn"}, id: "fn"},
+		{event: &eventstypes.ContainerDelete{ID: "fn"}, id: "fn"},
+		{event: &eventstypes.ContainerCreate{ID: "fn"}, id: ""},
+		{event: &eventstypes.TaskCheckpointed{ContainerID: "fn"}, id: ""},
+	}
+	for _, s := range scenarios {
+		if id := eventContainerID(s.event); id != s.id {
+			t.Fatalf("expected %T to concern %q, got %q", s.event, s.id, id)
+		}
+	}
+}
+
+// newTestResolver returns a resolver with the endpoints of names resolved.
+func newTestResolver(names ...string) *InvokeResolver {
+	i := NewCachedInvokeResolver(nil, time.Hour)
+	for _, name := range names {
+		i.endpoints[name] = endpoint{url: url.URL{Scheme: "http", Host: name}, resolvedAt: time.Now()}
+	}
+	return i
+}
+
+func (i *InvokeResolver) resolvedNames() []string {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	names := []string{}
+	for name := range i.endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func envelope(t *testing.T, topic string, event interface{}) *events.Envelope {
+	any, err := typeurl.MarshalAny(event)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	return &events.Envelope{Topic: topic, Namespace: FunctionNamespace, Event: any}
+}
+
+func Test_HandleEventInvalidatesEndpoints(t *testing.T) {
+	i := newTestResolver("a", "b", "c")
+
+	i.handleEvent(envelope(t, "/tasks/exit", &eventstypes.TaskExit{ContainerID: "a"}))
+	i.handleEvent(envelope(t, "/containers/create", &eventstypes.ContainerCreate{ID: "b"}))
+	undecodable := envelope(t, "/tasks/exit", &eventstypes.TaskExit{ContainerID: "c"})
+	undecodable.Event.TypeUrl = "unknown"
+	i.handleEvent(undecodable)
+	if names := i.resolvedNames(); len(names) != 2 || names[0] != "b" || names[1] != "c" {
+		t.Fatalf("expected only the endpoint of the exited task to be dropped, got %v", names)
+	}
+
+	i.handleEvent(envelope(t, "/tasks/delete", &eventstypes.TaskDelete{ContainerID: "c"}))
+	if names := i.resolvedNames(); len(names) != 1 || names[0] != "b" {
+		t.Fatalf("expected the endpoint of the deleted task to be dropped, got %v", names)
+	}
+}
+
+func Test_ResolveUsesResolvedEndpoints(t *testing.T) {
+	i := newTestResolver("a")
+
+	// the resolver has no client, only the endpoint table can answer
+	u, _, err := i.Resolve("a")
+	if err != nil || u.Host != "a" {
+		t.Fatalf("expected the resolved endpoint, got %v, %v", u, err)
+	}
+	if _, err := i.Lookup("a"); err != nil {
+		t.Fatalf("expected the resolved function, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"sort"
+
+	"faasd-agent/pkg/types"
+
+	"github.com/containerd/containerd"
+)
+
+// ReadFunctions returns the status of every function in FunctionNamespace, sorted by name.
+func ReadFunctions(client *containerd.Client) ([]types.FunctionStatus, error) {
+	fns, err := ListFunctions(client)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.FunctionStatus, 0, len(fns))
+	for _, fn := range fns {
+		res = append(res, functionStatus(*fn))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// ReadFunction returns the status of the function that matches name.
+func ReadFunction(client *containerd.Client, name string) (types.FunctionStatus, error) {
+	fn, err := GetFunction(client, name)
+	if err != nil {
+		return types.FunctionStatus{}, err
+	}
+	return functionStatus(fn), nil
+}
+
+// functionStatus converts fn to types.FunctionStatus, the invocation count is
+// left to the caller which tracks invocations.
+func functionStatus(fn Function) types.FunctionStatus {
+	labels := fn.labels
+	annotations := fn.annotations
+	return types.FunctionStatus{
+		Name:              fn.name,
+		Image:             fn.image,
+		Replicas:          uint64(fn.replicas),
+		AvailableReplicas: uint64(fn.replicas),
+		Labels:            &labels,
+		Annotations:       &annotations,
+		Namespace:         fn.namespace,
+	}
+}
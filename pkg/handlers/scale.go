@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"faasd-agent/pkg/cninetwork"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	gocni "github.com/containerd/go-cni"
+)
+
+const (
+	// readyPollInterval is how often the watchdog of a starting function is polled
+	readyPollInterval = 50 * time.Millisecond
+
+	// healthPath is the health endpoint of the watchdog
+	healthPath = "/_/health"
+)
+
+// ScaleUp makes sure the function has a running task: a paused task is
+// resumed, a stopped one is removed and created again, and a missing one is
+// created and attached to the CNI network.
+func ScaleUp(ctx context.Context, client *containerd.Client, cni gocni.CNI, name string) error {
+	ctx = namespaces.WithNamespace(ctx, FunctionNamespace)
+
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		return fmt.Errorf("unable to find function: %s, error %s", name, err)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("unable to get task of %s: %s", name, err)
+	}
+	if err == nil {
+		status, err := task.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to get task status for container: %s %s", name, err)
+		}
+
+		switch status.Status {
+		case containerd.Running:
+			return nil
+		case containerd.Paused:
+			log.Printf("[Scale] resuming task of %s\n", name)
+			return task.Resume(ctx)
+		case containerd.Stopped:
+			// stopped tasks cannot be restarted, they must be removed and created again
+			if err := cninetwork.DeleteCNINetwork(ctx, cni, client, name); err != nil {
+				log.Printf("[Scale] error removing CNI network for %s, %s\n", name, err)
+			}
+			if _, err := task.Delete(ctx); err != nil {
+				return fmt.Errorf("unable to delete stopped task of %s: %s", name, err)
+			}
+		default:
+			return fmt.Errorf("task of %s is %s", name, status.Status)
+		}
+	}
+
+	log.Printf("[Scale] creating task of %s\n", name)
+	return createTask(ctx, container, cni)
+}
+
+// WaitForReady polls the health endpoint of the watchdog at ip until it
+// answers with 200 OK or ctx is done.
+func WaitForReady(ctx context.Context, ip string) error {
+	client := &http.Client{Timeout: time.Second}
+	url := fmt.Sprintf("http://%s:%d%s", ip, watchdogPort, healthPath)
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		res, err := client.Do(req.WithContext(ctx))
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("watchdog at %s is not ready: %s", ip, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
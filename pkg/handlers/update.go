@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"faasd-agent/pkg/types"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	gocni "github.com/containerd/go-cni"
+)
+
+// Update replaces the container of a deployed function with one built from
+// req. The previous container is started again when req cannot be deployed.
+func Update(ctx context.Context, client *containerd.Client, cni gocni.CNI, req types.FunctionDeployment, secretMountPath string) error {
+	if err := validateDeployment(req); err != nil {
+		return err
+	}
+	name := req.Service
+	if _, err := GetFunction(client, name); err != nil {
+		return fmt.Errorf("function %s not found: %s", name, err)
+	}
+
+	// pull before removing the old container to keep the downtime short
+	if _, err := prepull(namespaces.WithNamespace(ctx, FunctionNamespace), client, req); err != nil {
+		return err
+	}
+
+	previous, err := savePrevious(namespaces.WithNamespace(ctx, FunctionNamespace), client, name)
+	if err != nil {
+		return err
+	}
+
+	if err := Delete(ctx, client, cni, name); err != nil {
+		return err
+	}
+
+	if err := Deploy(ctx, client, cni, req, secretMountPath); err != nil {
+		if restoreErr := previous.restore(client, cni); restoreErr != nil {
+			log.Printf("[Update] unable to restore %s after failing to update it: %s\n", name, restoreErr)
+		} else {
+			log.Printf("[Update] restored the previous deployment of %s\n", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// previousDeployment is what it takes to start the container of a function
+// again after a failed update.
+type previousDeployment struct {
+	name        string
+	image       containerd.Image
+	snapshotter string
+	spec        *oci.Spec
+	labels      map[string]string
+}
+
+func savePrevious(ctx context.Context, client *containerd.Client, name string) (*previousDeployment, error) {
+	container, err := client.LoadContainer(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load container %s: %s", name, err)
+	}
+	info, err := container.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get container %s: %s", name, err)
+	}
+	image, err := container.Image(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get image of %s: %s", name, err)
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get spec of %s: %s", name, err)
+	}
+	return &previousDeployment{name: name, image: image, snapshotter: info.Snapshotter, spec: spec, labels: info.Labels}, nil
+}
+
+// restore creates the container of the previous deployment and starts it.
+func (p *previousDeployment) restore(client *containerd.Client, cni gocni.CNI) error {
+	ctx := cleanupContext()
+	container, err := client.NewContainer(
+		ctx,
+		p.name,
+		containerd.WithImage(p.image),
+		containerd.WithSnapshotter(p.snapshotter),
+		containerd.WithNewSnapshot(p.name+"-snapshot", p.image),
+		containerd.WithSpec(p.spec),
+		containerd.WithContainerLabels(p.labels),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create container: %s, error: %s", p.name, err)
+	}
+	if err := createTask(ctx, container, cni); err != nil {
+		container.Delete(ctx, containerd.WithSnapshotCleanup)
+		return err
+	}
+	return nil
+}
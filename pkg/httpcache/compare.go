@@ -0,0 +1,63 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+)
+
+// VolatileHeaders are the response headers which change between executions
+// of a deterministic function, they are ignored by Equivalent.
+var VolatileHeaders = []string{
+	"Age",
+	"Date",
+	"Expires",
+	"Last-Modified",
+	"Set-Cookie",
+	"X-Call-Id",
+	"X-Duration-Seconds",
+	"X-Start-Time",
+}
+
+// Equivalent reports whether the responses serialized in HTTP/1.1 wire format
+// have the same status, headers and body, ignoring VolatileHeaders.
+func Equivalent(a, b []byte) bool {
+	resA, bodyA, errA := readResponse(a)
+	resB, bodyB, errB := readResponse(b)
+	if errA != nil || errB != nil {
+		return bytes.Equal(a, b)
+	}
+
+	if resA.StatusCode != resB.StatusCode || !bytes.Equal(bodyA, bodyB) {
+		return false
+	}
+	return reflect.DeepEqual(stableHeader(resA.Header), stableHeader(resB.Header))
+}
+
+// Failed reports whether the serialized response is a server error, or
+// cannot be read at all.
+func Failed(response []byte) bool {
+	res, _, err := readResponse(response)
+	return err != nil || res.StatusCode >= http.StatusInternalServerError
+}
+
+func readResponse(data []byte) (*http.Response, []byte, error) {
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	return res, body, err
+}
+
+// stableHeader returns header without VolatileHeaders.
+func stableHeader(header http.Header) http.Header {
+	stable := header.Clone()
+	for _, name := range VolatileHeaders {
+		stable.Del(name)
+	}
+	return stable
+}
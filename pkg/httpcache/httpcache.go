@@ -0,0 +1,165 @@
+// Package httpcache decides whether, and for how long, a shared cache may
+// store a function response according to its HTTP caching headers.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options are the settings of the cache storing the responses.
+type Options struct {
+	// NegativeTTL is how long non-2xx responses are cached, zero disables
+	// caching them
+	NegativeTTL time.Duration
+}
+
+// Decision is whether a response may be cached, and for how long.
+type Decision struct {
+	Cacheable bool
+	// TTL is how long the response is fresh, zero means it does not expire
+	TTL time.Duration
+	// Vary are the canonical names of the request headers the response depends on
+	Vary []string
+	// Reason explains why the response is not cacheable
+	Reason string
+}
+
+// EvaluateBytes evaluates the response serialized in HTTP/1.1 wire format.
+func EvaluateBytes(response []byte, now time.Time, options Options) Decision {
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(response)), nil)
+	if err != nil {
+		return Decision{Reason: "unparsable response"}
+	}
+	res.Body.Close()
+	return Evaluate(res, now, options)
+}
+
+// Evaluate applies the Cache-Control, Expires and Vary headers of res, and
+// its status, as a shared cache would.
+func Evaluate(res *http.Response, now time.Time, options Options) Decision {
+	directives := parseCacheControl(res.Header.Values("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return Decision{Reason: "no-store"}
+	}
+	if _, ok := directives["private"]; ok {
+		return Decision{Reason: "private"}
+	}
+	// the response must be revalidated before every use, the agent cannot do that
+	if _, ok := directives["no-cache"]; ok {
+		return Decision{Reason: "no-cache"}
+	}
+
+	vary := parseVary(res.Header.Values("Vary"))
+	for _, name := range vary {
+		if name == "*" {
+			return Decision{Reason: "vary *"}
+		}
+	}
+
+	ttl, explicit, ok := freshness(res.Header, directives, now)
+	if !ok {
+		return Decision{Reason: "expired"}
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		if options.NegativeTTL <= 0 {
+			return Decision{Reason: "status " + strconv.Itoa(res.StatusCode)}
+		}
+		if !explicit || ttl > options.NegativeTTL {
+			ttl = options.NegativeTTL
+		}
+	}
+
+	return Decision{Cacheable: true, TTL: ttl, Vary: vary}
+}
+
+// Lifetime returns how long res is fresh for a cache which revalidates stale
+// responses, and whether its headers set it. A response which must be
+// revalidated before every use, or is already stale, has an explicit
+// lifetime of zero.
+func Lifetime(res *http.Response, now time.Time) (ttl time.Duration, explicit bool) {
+	directives := parseCacheControl(res.Header.Values("Cache-Control"))
+	for _, name := range []string{"no-store", "no-cache"} {
+		if _, ok := directives[name]; ok {
+			return 0, true
+		}
+	}
+	ttl, explicit, ok := freshness(res.Header, directives, now)
+	if !ok {
+		return 0, true
+	}
+	return ttl, explicit
+}
+
+// freshness returns the lifetime of the response set by s-maxage, max-age or
+// Expires, in this order of precedence, and whether one is set. ok is false
+// when the response is already stale.
+func freshness(header http.Header, directives map[string]string, now time.Time) (ttl time.Duration, explicit bool, ok bool) {
+	for _, name := range []string{"s-maxage", "max-age"} {
+		value, found := directives[name]
+		if !found {
+			continue
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds <= 0 {
+			return 0, true, false
+		}
+		return time.Duration(seconds) * time.Second, true, true
+	}
+
+	if values := header.Values("Expires"); len(values) > 0 {
+		expires, err := http.ParseTime(values[0])
+		if err != nil {
+			// an invalid date means already expired
+			return 0, true, false
+		}
+		date := now
+		if d, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = d
+		}
+		if !expires.After(date) {
+			return 0, true, false
+		}
+		return expires.Sub(date), true, true
+	}
+
+	return 0, false, true
+}
+
+// parseCacheControl returns the lower-cased directives of the Cache-Control
+// headers with their unquoted values.
+func parseCacheControl(headers []string) map[string]string {
+	directives := make(map[string]string)
+	for _, header := range headers {
+		for _, part := range strings.Split(header, ",") {
+			part = strings.TrimSpace(part)
+			if len(part) == 0 {
+				continue
+			}
+			name, value := part, ""
+			if i := strings.Index(part, "="); i >= 0 {
+				name, value = part[:i], strings.Trim(strings.TrimSpace(part[i+1:]), `"`)
+			}
+			directives[strings.ToLower(strings.TrimSpace(name))] = value
+		}
+	}
+	return directives
+}
+
+// parseVary returns the canonical names of the Vary headers.
+func parseVary(headers []string) []string {
+	var names []string
+	for _, header := range headers {
+		for _, name := range strings.Split(header, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
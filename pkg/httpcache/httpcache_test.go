@@ -0,0 +1,156 @@
+package httpcache
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func response(status int, header http.Header) *http.Response {
+	return &http.Response{StatusCode: status, Header: header}
+}
+
+func Test_EvaluateCacheControl(t *testing.T) {
+	cases := []struct {
+		name     string
+		header   http.Header
+		expected Decision
+	}{
+		{"no headers", http.Header{}, Decision{Cacheable: true}},
+		{"no-store", http.Header{"Cache-Control": {"max-age=60, no-store"}}, Decision{Reason: "no-store"}},
+		{"private", http.Header{"Cache-Control": {"private, max-age=60"}}, Decision{Reason: "private"}},
+		{"no-cache", http.Header{"Cache-Control": {"No-Cache"}}, Decision{Reason: "no-cache"}},
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=60"}}, Decision{Cacheable: true, TTL: time.Minute}},
+		{"quoted max-age", http.Header{"Cache-Control": {`max-age="30"`}}, Decision{Cacheable: true, TTL: 30 * time.Second}},
+		{"s-maxage wins", http.Header{"Cache-Control": {"max-age=60", "s-maxage=10"}}, Decision{Cacheable: true, TTL: 10 * time.Second}},
+		{"max-age zero", http.Header{"Cache-Control": {"max-age=0"}}, Decision{Reason: "expired"}},
+		{"max-age wins over expires", http.Header{
+			"Cache-Control": {"max-age=5"},
+			"Expires":       {now.Add(time.Hour).Format(http.TimeFormat)},
+		}, Decision{Cacheable: true, TTL: 5 * time.Second}},
+	}
+
+	for _, c := range cases {
+		if d := Evaluate(response(http.StatusOK, c.header), now, Options{}); !reflect.DeepEqual(d, c.expected) {
+			t.Fatalf("%s: expected %+v, got %+v", c.name, c.expected, d)
+		}
+	}
+}
+
+func Test_EvaluateExpires(t *testing.T) {
+	header := http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}
+	if d := Evaluate(response(http.StatusOK, header), now, Options{}); d.TTL != time.Hour {
+		t.Fatalf("expected 1h, got %+v", d)
+	}
+
+	// the lifetime is relative to the Date of the response
+	header.Set("Date", now.Add(-30*time.Minute).Format(http.TimeFormat))
+	if d := Evaluate(response(http.StatusOK, header), now, Options{}); d.TTL != 90*time.Minute {
+		t.Fatalf("expected 1h30m, got %+v", d)
+	}
+
+	for _, expires := range []string{"0", now.Add(-time.Second).Format(http.TimeFormat)} {
+		header := http.Header{"Expires": {expires}}
+		if d := Evaluate(response(http.StatusOK, header), now, Options{}); d.Cacheable {
+			t.Fatalf("%s: expected an expired response, got %+v", expires, d)
+		}
+	}
+}
+
+func Test_EvaluateStatus(t *testing.T) {
+	if d := Evaluate(response(http.StatusInternalServerError, http.Header{}), now, Options{}); d.Cacheable {
+		t.Fatalf("expected 500 not to be cached, got %+v", d)
+	}
+
+	options := Options{NegativeTTL: 10 * time.Second}
+	if d := Evaluate(response(http.StatusNotFound, http.Header{}), now, options); !d.Cacheable || d.TTL != 10*time.Second {
+		t.Fatalf("expected 404 to be cached for 10s, got %+v", d)
+	}
+	header := http.Header{"Cache-Control": {"max-age=5"}}
+	if d := Evaluate(response(http.StatusNotFound, header), now, options); d.TTL != 5*time.Second {
+		t.Fatalf("expected a shorter max-age to win, got %+v", d)
+	}
+	header = http.Header{"Cache-Control": {"no-store"}}
+	if d := Evaluate(response(http.StatusNotFound, header), now, options); d.Cacheable {
+		t.Fatalf("expected no-store to win over negative caching, got %+v", d)
+	}
+}
+
+func Test_EvaluateVary(t *testing.T) {
+	header := http.Header{"Vary": {"accept-encoding, Accept", "X-Tenant-Id"}}
+	d := Evaluate(response(http.StatusOK, header), now, Options{})
+	expected := []string{"Accept-Encoding", "Accept", "X-Tenant-Id"}
+	if !d.Cacheable || !reflect.DeepEqual(d.Vary, expected) {
+		t.Fatalf("expected %v, got %+v", expected, d)
+	}
+
+	header = http.Header{"Vary": {"Accept, *"}}
+	if d := Evaluate(response(http.StatusOK, header), now, Options{}); d.Cacheable {
+		t.Fatalf("expected vary * not to be cached, got %+v", d)
+	}
+}
+
+func Test_EvaluateBytes(t *testing.T) {
+	raw := "HTTP/1.1 200 OK\r\nCache-Control: max-age=60\r\nContent-Length: 2\r\n\r\nok"
+	if d := EvaluateBytes([]byte(raw), now, Options{}); !d.Cacheable || d.TTL != time.Minute {
+		t.Fatalf("expected a cacheable response for 1m, got %+v", d)
+	}
+	if d := EvaluateBytes([]byte("garbage"), now, Options{}); d.Cacheable {
+		t.Fatalf("expected an unparsable response not to be cached, got %+v", d)
+	}
+}
+
+func Test_Equivalent(t *testing.T) {
+	cached := "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nDate: Mon, 01 Mar 2021 12:00:00 GMT\r\nX-Duration-Seconds: 0.1\r\nContent-Length: 2\r\n\r\nok"
+	same := "HTTP/1.1 200 OK\r\nDate: Mon, 01 Mar 2021 12:05:00 GMT\r\nX-Duration-Seconds: 0.3\r\nContent-Type: text/plain\r\nContent-Length: 2\r\n\r\nok"
+	if !Equivalent([]byte(cached), []byte(same)) {
+		t.Fatalf("expected responses differing by volatile headers to be equivalent")
+	}
+
+	different := map[string]string{
+		"body":   "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 2\r\n\r\nko",
+		"status": "HTTP/1.1 500 Internal Server Error\r\nContent-Type: text/plain\r\nContent-Length: 2\r\n\r\nok",
+		"header": "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 2\r\n\r\nok",
+	}
+	for name, response := range different {
+		if Equivalent([]byte(cached), []byte(response)) {
+			t.Fatalf("%s: expected responses not to be equivalent", name)
+		}
+	}
+}
+
+func Test_Failed(t *testing.T) {
+	responses := map[string]bool{
+		"HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok":          false,
+		"HTTP/1.1 404 Not Found\r\nContent-Length: 2\r\n\r\nno":   false,
+		"HTTP/1.1 502 Bad Gateway\r\nContent-Length: 2\r\n\r\nko": true,
+		"garbage": true,
+	}
+	for response, expected := range responses {
+		if failed := Failed([]byte(response)); failed != expected {
+			t.Fatalf("%q: expected %v, got %v", response, expected, failed)
+		}
+	}
+}
+
+func Test_Lifetime(t *testing.T) {
+	cases := []struct {
+		header   http.Header
+		ttl      time.Duration
+		explicit bool
+	}{
+		{http.Header{"Cache-Control": {"max-age=60"}}, time.Minute, true},
+		{http.Header{"Cache-Control": {"no-cache"}}, 0, true},
+		{http.Header{"Cache-Control": {"max-age=0"}}, 0, true},
+		{http.Header{"Etag": {`"v1"`}}, 0, false},
+	}
+	for _, c := range cases {
+		ttl, explicit := Lifetime(response(200, c.header), now)
+		if ttl != c.ttl || explicit != c.explicit {
+			t.Fatalf("%v: expected %s, %v, got %s, %v", c.header, c.ttl, c.explicit, ttl, explicit)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	procStat    = "/proc/stat"
+	procMeminfo = "/proc/meminfo"
+	procLoadavg = "/proc/loadavg"
+)
+
+// HostStats describes the CPU and memory usage of the host running the agent.
+type HostStats struct {
+	// CPUUsage is the fraction of CPU time spent busy since the previous sample.
+	CPUUsage             float64
+	MemoryTotalBytes     uint64
+	MemoryAvailableBytes uint64
+	Load1                float64
+}
+
+// HostSampler reads host statistics from procfs. CPU usage is computed from the
+// difference between two consecutive samples, it is zero for the first one.
+type HostSampler struct {
+	mutex     sync.Mutex
+	lastBusy  uint64
+	lastTotal uint64
+
+	// latest is the most recent sample taken by Run, latestErr its error
+	latest    HostStats
+	latestErr error
+}
+
+// Run samples the host every interval until ctx is done, so every reader of
+// Latest sees the CPU usage over the same interval.
+func (h *HostSampler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		stats, err := h.Sample()
+		h.mutex.Lock()
+		h.latest, h.latestErr = stats, err
+		h.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Latest returns the most recent sample taken by Run.
+func (h *HostSampler) Latest() (HostStats, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.latest, h.latestErr
+}
+
+// Sample returns the current host statistics.
+func (h *HostSampler) Sample() (HostStats, error) {
+	stats := HostStats{}
+
+	busy, total, err := readCPUTimes()
+	if err != nil {
+		return stats, err
+	}
+	h.mutex.Lock()
+	if h.lastTotal > 0 && total > h.lastTotal {
+		stats.CPUUsage = float64(busy-h.lastBusy) / float64(total-h.lastTotal)
+	}
+	h.lastBusy, h.lastTotal = busy, total
+	h.mutex.Unlock()
+
+	stats.MemoryTotalBytes, stats.MemoryAvailableBytes, err = readMemory()
+	if err != nil {
+		return stats, err
+	}
+
+	loadavg, err := ioutil.ReadFile(procLoadavg)
+	if err != nil {
+		return stats, err
+	}
+	if fields := strings.Fields(string(loadavg)); len(fields) > 0 {
+		stats.Load1, _ = strconv.ParseFloat(fields[0], 64)
+	}
+
+	return stats, nil
+}
+
+// readCPUTimes returns the busy and total jiffies of the aggregated cpu line of /proc/stat.
+func readCPUTimes() (uint64, uint64, error) {
+	data, err := ioutil.ReadFile(procStat)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	line := strings.SplitN(string(data), "\n", 2)[0]
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected %s format: %q", procStat, line)
+	}
+
+	var busy, total uint64
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("unexpected %s value %q: %s", procStat, field, err)
+		}
+		total += v
+		// idle and iowait are the 4th and 5th values
+		if i != 3 && i != 4 {
+			busy += v
+		}
+	}
+	return busy, total, nil
+}
+
+// readMemory returns MemTotal and MemAvailable of /proc/meminfo in bytes.
+func readMemory() (uint64, uint64, error) {
+	f, err := os.Open(procMeminfo)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var total, available uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = v * 1024
+		case "MemAvailable:":
+			available = v * 1024
+		}
+	}
+	return total, available, scanner.Err()
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_HostSamplerRunKeepsLatestSample(t *testing.T) {
+	if _, err := os.Stat(procStat); err != nil {
+		t.Skipf("%s is not available", procStat)
+	}
+
+	h := &HostSampler{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.Run(ctx, 20*time.Millisecond)
+		close(done)
+	}()
+	defer cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		stats, err := h.Latest()
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if stats.MemoryTotalBytes > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a sample to be taken")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// readers share the sample instead of taking their own
+	cancel()
+	<-done
+	first, _ := h.Latest()
+	time.Sleep(20 * time.Millisecond)
+	if second, _ := h.Latest(); second != first {
+		t.Fatalf("expected the same sample, got %+v and %+v", first, second)
+	}
+}
+
+func Test_HostSamplerFirstSampleHasNoCPUUsage(t *testing.T) {
+	if _, err := os.Stat(procStat); err != nil {
+		t.Skipf("%s is not available", procStat)
+	}
+
+	stats, err := (&HostSampler{}).Sample()
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if stats.CPUUsage != 0 {
+		t.Fatalf("expected no CPU usage without a previous sample, got %f", stats.CPUUsage)
+	}
+}
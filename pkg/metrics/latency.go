@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencyWindow keeps the most recent latency samples in a ring buffer so
+// percentiles reflect the current behaviour of a function.
+type LatencyWindow struct {
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+// NewLatencyWindow creates a LatencyWindow holding up to size samples.
+func NewLatencyWindow(size int) *LatencyWindow {
+	if size < 1 {
+		size = 1
+	}
+	return &LatencyWindow{samples: make([]time.Duration, size)}
+}
+
+// Add records a sample, overwriting the oldest one when the window is full.
+func (w *LatencyWindow) Add(d time.Duration) {
+	w.samples[w.next] = d
+	w.next++
+	if w.next == len(w.samples) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+// Len returns the number of samples in the window.
+func (w *LatencyWindow) Len() int {
+	if w.full {
+		return len(w.samples)
+	}
+	return w.next
+}
+
+// Percentiles returns the nearest-rank percentile for each p in ps, where p is
+// in the range (0, 100]. It returns zeros when the window is empty.
+func (w *LatencyWindow) Percentiles(ps ...float64) []time.Duration {
+	res := make([]time.Duration, len(ps))
+	n := w.Len()
+	if n == 0 {
+		return res
+	}
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, w.samples[:n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for i, p := range ps {
+		rank := int(math.Ceil(p/100*float64(n))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		if rank >= n {
+			rank = n - 1
+		}
+		res[i] = sorted[rank]
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_PercentilesEmptyWindow(t *testing.T) {
+	w := NewLatencyWindow(4)
+	ps := w.Percentiles(50, 99)
+	if ps[0] != 0 || ps[1] != 0 {
+		t.Fatalf("expected zero percentiles, got %v", ps)
+	}
+}
+
+func Test_PercentilesNearestRank(t *testing.T) {
+	w := NewLatencyWindow(100)
+	for i := 100; i >= 1; i-- {
+		w.Add(time.Duration(i) * time.Millisecond)
+	}
+
+	ps := w.Percentiles(50, 90, 99, 100)
+	expected := []time.Duration{50 * time.Millisecond, 90 * time.Millisecond, 99 * time.Millisecond, 100 * time.Millisecond}
+	for i := range expected {
+		if ps[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected[i], ps[i])
+		}
+	}
+}
+
+func Test_WindowKeepsMostRecentSamples(t *testing.T) {
+	w := NewLatencyWindow(3)
+	for _, d := range []time.Duration{100, 100, 100, 1, 2, 3} {
+		w.Add(d)
+	}
+
+	if w.Len() != 3 {
+		t.Fatalf("expected 3 samples, got %d", w.Len())
+	}
+	if p := w.Percentiles(100)[0]; p != 3 {
+		t.Fatalf("expected max 3, got %v", p)
+	}
+}
@@ -0,0 +1,178 @@
+// Package metrics keeps the load and latency figures the agent reports to the
+// scheduler.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FunctionSnapshot is a point in time view of the load of one function.
+type FunctionSnapshot struct {
+	Name         string
+	InFlight     int64
+	Invocations  int64
+	Samples      int
+	P50          time.Duration
+	P90          time.Duration
+	P99          time.Duration
+	ColdStarts   int64
+	ColdStartP50 time.Duration
+	ColdStartP99 time.Duration
+	CacheHits    int64
+	// CacheSaved is the execution time the cached results of the function saved
+	CacheSaved time.Duration
+	// Verifications counts the cache hits re-executed to verify the cached
+	// result, Divergences those whose result differed
+	Verifications int64
+	Divergences   int64
+}
+
+type functionMetrics struct {
+	inFlight           int64
+	invocations        int64
+	latencies          *LatencyWindow
+	coldStarts         int64
+	coldStartLatencies *LatencyWindow
+	cacheHits          int64
+	cacheSaved         time.Duration
+	verifications      int64
+	divergences        int64
+}
+
+// Tracker records in-flight tasks and recent execution latencies per function.
+type Tracker struct {
+	mutex      sync.Mutex
+	windowSize int
+	functions  map[string]*functionMetrics
+}
+
+// NewTracker creates a Tracker which keeps windowSize latency samples per function.
+func NewTracker(windowSize int) *Tracker {
+	return &Tracker{windowSize: windowSize, functions: make(map[string]*functionMetrics)}
+}
+
+// Begin counts an invocation of the function and marks it as in flight, the
+// returned func marks it done.
+func (t *Tracker) Begin(name string) func() {
+	t.mutex.Lock()
+	f := t.function(name)
+	f.inFlight++
+	f.invocations++
+	t.mutex.Unlock()
+
+	return func() {
+		t.mutex.Lock()
+		t.function(name).inFlight--
+		t.mutex.Unlock()
+	}
+}
+
+// Observe records the execution latency of one invocation of the function.
+func (t *Tracker) Observe(name string, d time.Duration) {
+	t.mutex.Lock()
+	t.function(name).latencies.Add(d)
+	t.mutex.Unlock()
+}
+
+// ObserveColdStart records how long it took to start the function from zero replicas.
+func (t *Tracker) ObserveColdStart(name string, d time.Duration) {
+	t.mutex.Lock()
+	f := t.function(name)
+	f.coldStarts++
+	f.coldStartLatencies.Add(d)
+	t.mutex.Unlock()
+}
+
+// ObserveCacheHit records an invocation of the function answered from the
+// cache, saving an execution which took d.
+func (t *Tracker) ObserveCacheHit(name string, d time.Duration) {
+	t.mutex.Lock()
+	f := t.function(name)
+	f.cacheHits++
+	f.cacheSaved += d
+	t.mutex.Unlock()
+}
+
+// ObserveVerification records the re-execution of a cache hit of the
+// function, and whether its result differed from the cached one. It returns
+// the verifications and divergences of the function so far.
+func (t *Tracker) ObserveVerification(name string, diverged bool) (verifications, divergences int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	f := t.function(name)
+	f.verifications++
+	if diverged {
+		f.divergences++
+	}
+	return f.verifications, f.divergences
+}
+
+// Invocations returns the number of invocations of the function since the agent started.
+func (t *Tracker) Invocations(name string) int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if f, ok := t.functions[name]; ok {
+		return f.invocations
+	}
+	return 0
+}
+
+// InFlight returns the number of in-flight tasks over all functions.
+func (t *Tracker) InFlight() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	total := int64(0)
+	for _, f := range t.functions {
+		total += f.inFlight
+	}
+	return total
+}
+
+// Snapshot returns the load of every function seen so far, sorted by name.
+func (t *Tracker) Snapshot() []FunctionSnapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	snapshots := make([]FunctionSnapshot, 0, len(t.functions))
+	for name, f := range t.functions {
+		ps := f.latencies.Percentiles(50, 90, 99)
+		coldPs := f.coldStartLatencies.Percentiles(50, 99)
+		snapshots = append(snapshots, FunctionSnapshot{
+			Name:         name,
+			InFlight:     f.inFlight,
+			Invocations:  f.invocations,
+			Samples:      f.latencies.Len(),
+			P50:          ps[0],
+			P90:          ps[1],
+			P99:          ps[2],
+			ColdStarts:   f.coldStarts,
+			ColdStartP50: coldPs[0],
+			ColdStartP99: coldPs[1],
+			CacheHits:    f.cacheHits,
+			CacheSaved:   f.cacheSaved,
+
+			Verifications: f.verifications,
+			Divergences:   f.divergences,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+// function returns the metrics of name, creating them if needed. The caller must hold the mutex.
+func (t *Tracker) function(name string) *functionMetrics {
+	f, ok := t.functions[name]
+	if !ok {
+		f = &functionMetrics{
+			latencies:          NewLatencyWindow(t.windowSize),
+			coldStartLatencies: NewLatencyWindow(t.windowSize),
+		}
+		t.functions[name] = f
+	}
+	return f
+}
@@ -0,0 +1,188 @@
+// Package origins maps the input file URLs of tasks to the upstreams the file
+// caching proxy fetches them from.
+//
+// An input URL http://host/path is served by the proxy under /host/path, the
+// task bodies are rewritten accordingly before they reach the function.
+package origins
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Rule maps the input URLs matching a host and path prefix to an upstream.
+type Rule struct {
+	// Host matches the host of input URLs, empty matches any host
+	Host string
+	// PathPrefix matches the path of input URLs, it is replaced by the path
+	// of Upstream when the file is fetched
+	PathPrefix string
+	// Upstream is the base URL the files are fetched from
+	Upstream *url.URL
+	// MaxAge is how long a file is fresh when the upstream does not say,
+	// zero means it never has to be revalidated
+	MaxAge time.Duration
+	// StaleIfError is how long past its freshness a file is still served
+	// when the upstream cannot revalidate it
+	StaleIfError time.Duration
+}
+
+// Rules are matched in order, the first matching rule applies.
+type Rules []Rule
+
+// Parse parses rules of the form [host][/path-prefix]=upstream[;option=value],
+// such as example.com/assets/=http://10.0.0.5:8080/assets/ or
+// /datasets/=http://storage/;max-age=1h;stale-if-error=24h.
+func Parse(specs []string) (Rules, error) {
+	rules := make(Rules, 0, len(specs))
+	for _, spec := range specs {
+		options := strings.Split(spec, ";")
+		parts := strings.SplitN(options[0], "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid origin rule %q, expected [host][/path-prefix]=upstream", spec)
+		}
+
+		upstream, err := url.Parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid upstream of origin rule %q: %v", spec, err)
+		}
+		if (upstream.Scheme != "http" && upstream.Scheme != "https") || len(upstream.Host) == 0 {
+			return nil, fmt.Errorf("invalid upstream of origin rule %q, expected an absolute http(s) URL", spec)
+		}
+
+		rule := Rule{Host: parts[0], PathPrefix: "/", Upstream: upstream}
+		if i := strings.Index(parts[0], "/"); i >= 0 {
+			rule.Host, rule.PathPrefix = parts[0][:i], parts[0][i:]
+		}
+		for _, option := range options[1:] {
+			if err := rule.setOption(strings.TrimSpace(option)); err != nil {
+				return nil, fmt.Errorf("invalid option of origin rule %q: %v", spec, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (rule *Rule) setOption(option string) error {
+	parts := strings.SplitN(option, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected name=value, got %q", option)
+	}
+	d, err := time.ParseDuration(parts[1])
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q", parts[1])
+	}
+	switch parts[0] {
+	case "max-age":
+		rule.MaxAge = d
+	case "stale-if-error":
+		rule.StaleIfError = d
+	default:
+		return fmt.Errorf("unknown option %q", parts[0])
+	}
+	return nil
+}
+
+// Match returns the first rule matching the input URL u.
+func (r Rules) Match(u *url.URL) (Rule, bool) {
+	for _, rule := range r {
+		if len(rule.Host) > 0 && !strings.EqualFold(rule.Host, u.Host) {
+			continue
+		}
+		if strings.HasPrefix(u.Path, rule.PathPrefix) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// UpstreamURL returns the URL the input URL u is fetched from.
+func (rule Rule) UpstreamURL(u *url.URL) string {
+	upstream := *rule.Upstream
+	rest := strings.TrimPrefix(u.Path, rule.PathPrefix)
+	upstream.Path = strings.TrimSuffix(upstream.Path, "/") + "/" + strings.TrimPrefix(rest, "/")
+	upstream.RawPath = ""
+	upstream.RawQuery = u.RawQuery
+	return upstream.String()
+}
+
+// Resolve returns the input URL rawURL with its path cleaned, and the rule it
+// matches. It is false when rawURL is not an absolute URL or no rule matches.
+func (r Rules) Resolve(rawURL string) (*url.URL, Rule, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || len(u.Host) == 0 {
+		return nil, Rule{}, false
+	}
+	cleanPath(u)
+	rule, ok := r.Match(u)
+	return u, rule, ok
+}
+
+// Key returns the key the file of the input URL rawURL is cached under, it
+// is false when no rule matches rawURL.
+func (r Rules) Key(rawURL string) (string, bool) {
+	u, _, ok := r.Resolve(rawURL)
+	if !ok {
+		return "", false
+	}
+	return CacheKey(u), true
+}
+
+// CacheKey returns the key the file of the input URL u is cached under, the
+// proxy serves it under /key.
+func CacheKey(u *url.URL) string {
+	k := strings.ToLower(u.Host) + u.EscapedPath()
+	if len(u.RawQuery) > 0 {
+		k += "?" + u.RawQuery
+	}
+	return k
+}
+
+// FromProxyPath returns the input URL served by the proxy under path and
+// query, and the rule it matches.
+func (r Rules) FromProxyPath(path, rawQuery string) (*url.URL, Rule, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts[0]) == 0 {
+		return nil, Rule{}, fmt.Errorf("no origin host in %q", path)
+	}
+	u := &url.URL{Scheme: "http", Host: parts[0], Path: "/", RawQuery: rawQuery}
+	if len(parts) == 2 {
+		u.Path += parts[1]
+	}
+	cleanPath(u)
+	rule, ok := r.Match(u)
+	if !ok {
+		return nil, Rule{}, fmt.Errorf("no origin rule matches %s", CacheKey(u))
+	}
+	return u, rule, nil
+}
+
+// cleanPath resolves the . and .. segments of the path of u, so a path
+// cannot escape the prefix of the rule it matches.
+func cleanPath(u *url.URL) {
+	cleaned := path.Clean("/" + u.Path)
+	if strings.HasSuffix(u.Path, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	u.Path, u.RawPath = cleaned, ""
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>\\]+`)
+
+// Rewrite replaces the input URLs matching a rule in body by their URL on the
+// proxy at proxyBase, such as http://192.168.1.10:8080.
+func (r Rules) Rewrite(body []byte, proxyBase string) []byte {
+	proxyBase = strings.TrimSuffix(proxyBase, "/")
+	return urlPattern.ReplaceAllFunc(body, func(match []byte) []byte {
+		k, ok := r.Key(string(match))
+		if !ok {
+			return match
+		}
+		return []byte(proxyBase + "/" + k)
+	})
+}
@@ -0,0 +1,83 @@
+package origins
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parse(t *testing.T) {
+	rules, err := Parse([]string{"mvatandoosts.ir/assets/images/=http://mvatandoosts.ir/assets/images/", "/datasets/=https://storage:9000/data", "files.local=http://10.0.0.5/"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := []Rule{
+		{Host: "mvatandoosts.ir", PathPrefix: "/assets/images/"},
+		{Host: "", PathPrefix: "/datasets/"},
+		{Host: "files.local", PathPrefix: "/"},
+	}
+	for i, rule := range rules {
+		if rule.Host != expected[i].Host || rule.PathPrefix != expected[i].PathPrefix {
+			t.Fatalf("rule %d: expected %+v, got %+v", i, expected[i], rule)
+		}
+	}
+
+	rules, err = Parse([]string{"/datasets/=http://storage/;max-age=1h;stale-if-error=24h"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if rules[0].MaxAge != time.Hour || rules[0].StaleIfError != 24*time.Hour || rules[0].Upstream.String() != "http://storage/" {
+		t.Fatalf("expected 1h and 24h for http://storage/, got %+v", rules[0])
+	}
+
+	for _, spec := range []string{"no-upstream", "=http://x/", "/a/=ftp://x/", "/a/=relative/path", "/a/=http://x/;max-age=soon", "/a/=http://x/;ttl=1h"} {
+		if _, err := Parse([]string{spec}); err == nil {
+			t.Fatalf("%q: expected an error", spec)
+		}
+	}
+}
+
+func Test_FromProxyPath(t *testing.T) {
+	rules, _ := Parse([]string{"/datasets/=https://storage:9000/data"})
+
+	u, rule, err := rules.FromProxyPath("/any.host/datasets/cats/1.jpg", "v=2")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if upstream := rule.UpstreamURL(u); upstream != "https://storage:9000/data/cats/1.jpg?v=2" {
+		t.Fatalf("expected https://storage:9000/data/cats/1.jpg?v=2, got %s", upstream)
+	}
+
+	if _, _, err := rules.FromProxyPath("/any.host/other/1.jpg", ""); err == nil {
+		t.Fatalf("expected an error for a path no rule matches")
+	}
+
+	rules, _ = Parse([]string{"mvatandoosts.ir/assets/images/=http://mvatandoosts.ir/assets/images/"})
+	for _, p := range []string{"/mvatandoosts.ir/assets/images/../../admin/secret", "/mvatandoosts.ir/assets/images/../images2/1.jpg"} {
+		if u, _, err := rules.FromProxyPath(p, ""); err == nil {
+			t.Fatalf("%s: expected an error for a path escaping the prefix, got %s", p, u)
+		}
+	}
+	u, rule, err = rules.FromProxyPath("/mvatandoosts.ir/assets/images/cats/../I1.jpg", "")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if upstream := rule.UpstreamURL(u); upstream != "http://mvatandoosts.ir/assets/images/I1.jpg" {
+		t.Fatalf("expected http://mvatandoosts.ir/assets/images/I1.jpg, got %s", upstream)
+	}
+	if k := CacheKey(u); k != "mvatandoosts.ir/assets/images/I1.jpg" {
+		t.Fatalf("expected mvatandoosts.ir/assets/images/I1.jpg, got %s", k)
+	}
+	if _, ok := rules.Key("http://mvatandoosts.ir/assets/images/../../admin/secret"); ok {
+		t.Fatalf("expected no key for a URL escaping the prefix")
+	}
+}
+
+func Test_Rewrite(t *testing.T) {
+	rules, _ := Parse([]string{"mvatandoosts.ir/assets/images/=http://mvatandoosts.ir/assets/images/"})
+
+	body := `{"url": "http://mvatandoosts.ir/assets/images/I1.jpg", "other": "http://example.com/I1.jpg"}`
+	expected := `{"url": "http://10.0.0.1:8080/mvatandoosts.ir/assets/images/I1.jpg", "other": "http://example.com/I1.jpg"}`
+	if rewritten := string(rules.Rewrite([]byte(body), "http://10.0.0.1:8080/")); rewritten != expected {
+		t.Fatalf("expected %s, got %s", expected, rewritten)
+	}
+}
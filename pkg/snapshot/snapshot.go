@@ -0,0 +1,113 @@
+// Package snapshot reads and writes cache snapshot files, which hold the
+// entries of a result cache for warming another agent or for offline
+// analysis. A snapshot is a JSON header line followed by one JSON line per
+// entry, from the least to the most recently used.
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Version is the version of the snapshot format written by Writer.
+const Version = 1
+
+// maxLineSize bounds the size of one entry line, values included.
+const maxLineSize = 256 * 1024 * 1024
+
+// Header describes a snapshot.
+type Header struct {
+	Version             int   `json:"version"`
+	CreatedAtNanoSecond int64 `json:"createdAtNanoSecond"`
+	// Values is whether the entries carry their cached value
+	Values bool `json:"values"`
+}
+
+// Entry is a cached result.
+type Entry struct {
+	Key                     string `json:"key"`
+	FunctionName            string `json:"functionName,omitempty"`
+	Size                    int64  `json:"size"`
+	CreatedAtNanoSecond     int64  `json:"createdAtNanoSecond"`
+	ExpiresAtNanoSecond     int64  `json:"expiresAtNanoSecond,omitempty"`
+	ExecutionTimeNanoSecond int64  `json:"executionTimeNanoSecond"`
+	Hits                    uint64 `json:"hits"`
+	// Vary marks an entry without value pointing to the responses which
+	// depend on these request headers
+	Vary  []string `json:"vary,omitempty"`
+	Value []byte   `json:"value,omitempty"`
+	// Image is the function image which produced the result
+	Image string `json:"image,omitempty"`
+	// StaleUntilNanoSecond is when the expired result can no longer be
+	// served stale
+	StaleUntilNanoSecond int64 `json:"staleUntilNanoSecond,omitempty"`
+}
+
+// Writer writes a snapshot.
+type Writer struct {
+	encoder *json.Encoder
+}
+
+// NewWriter writes header to w and returns a Writer for the entries.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	header.Version = Version
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	return &Writer{encoder: encoder}, nil
+}
+
+// Write writes one entry.
+func (w *Writer) Write(e Entry) error {
+	return w.encoder.Encode(e)
+}
+
+// Reader reads a snapshot.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader reads the header of the snapshot in r and returns a Reader for
+// its entries.
+func NewReader(r io.Reader) (*Reader, Header, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	reader := &Reader{scanner: scanner}
+
+	var header Header
+	if err := reader.next(&header); err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("empty snapshot")
+		}
+		return nil, Header{}, err
+	}
+	if header.Version != Version {
+		return nil, Header{}, fmt.Errorf("unsupported snapshot version %d", header.Version)
+	}
+	return reader, header, nil
+}
+
+// Read returns the next entry, or io.EOF after the last one.
+func (r *Reader) Read() (Entry, error) {
+	var e Entry
+	err := r.next(&e)
+	return e, err
+}
+
+func (r *Reader) next(v interface{}) error {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	r.line++
+	if err := json.Unmarshal(r.scanner.Bytes(), v); err != nil {
+		return fmt.Errorf("invalid snapshot line %d: %s", r.line, err)
+	}
+	return nil
+}
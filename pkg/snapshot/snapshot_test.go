@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_RoundTrip(t *testing.T) {
+	entries := []Entry{
+		{Key: "a", FunctionName: "shasum", Size: 2, CreatedAtNanoSecond: 1, ExecutionTimeNanoSecond: 5, ExpiresAtNanoSecond: 7, StaleUntilNanoSecond: 9, Hits: 3, Value: []byte("ok")},
+		{Key: "b", Vary: []string{"Accept"}, Size: 6},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{CreatedAtNanoSecond: 42, Values: true})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	r, header, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if header != (Header{Version: Version, CreatedAtNanoSecond: 42, Values: true}) {
+		t.Fatalf("unexpected header %+v", header)
+	}
+
+	var read []Entry
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		read = append(read, e)
+	}
+	if !reflect.DeepEqual(read, entries) {
+		t.Fatalf("expected %+v, got %+v", entries, read)
+	}
+}
+
+func Test_ReaderRejectsInvalidSnapshots(t *testing.T) {
+	for _, snapshot := range []string{"", `{"version": 2}`, "not json"} {
+		if _, _, err := NewReader(strings.NewReader(snapshot)); err == nil {
+			t.Fatalf("%q: expected an error", snapshot)
+		}
+	}
+
+	r, _, err := NewReader(strings.NewReader("{\"version\": 1}\n{\"key\": 1}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if _, err := r.Read(); err == nil || err == io.EOF {
+		t.Fatalf("expected an invalid entry error, got %v", err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	units "github.com/docker/go-units"
 )
 
 // OsEnv implements interface to wrap os.Getenv
@@ -55,6 +58,31 @@ func ParseIntOrDurationValue(val string, fallback time.Duration) time.Duration {
 	return duration
 }
 
+// ParseBytesValue parses the size in val, a number of bytes or a
+// human-readable size such as "64m" or "1GiB", or, if there is an error,
+// returns the specified default value
+func ParseBytesValue(val string, fallback int64) int64 {
+	if len(val) > 0 {
+		parsedVal, parseErr := units.RAMInBytes(val)
+		if parseErr == nil && parsedVal >= 0 {
+			return parsedVal
+		}
+	}
+	return fallback
+}
+
+// ParseFloatValue parses the non-negative float in val or, if there is an error,
+// returns the specified default value
+func ParseFloatValue(val string, fallback float64) float64 {
+	if len(val) > 0 {
+		parsedVal, parseErr := strconv.ParseFloat(val, 64)
+		if parseErr == nil && parsedVal >= 0 {
+			return parsedVal
+		}
+	}
+	return fallback
+}
+
 // ParseBoolValue parses the the boolean in val or, if there is an error, returns the
 // specified default value
 func ParseBoolValue(val string, fallback bool) bool {
@@ -73,6 +101,21 @@ func ParseString(val string, fallback string) string {
 	return fallback
 }
 
+// ParseStringList parses the comma-separated list in val, ignoring empty items,
+// or, if there is no item, returns the specified default value
+func ParseStringList(val string, fallback []string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return fallback
+	}
+	return items
+}
+
 // Read fetches config from environmental variables.
 func (ReadConfig) Read(hasEnv HasEnv) (*FaaSConfig, error) {
 	cfg := &FaaSConfig{
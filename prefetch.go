@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// PrefetchInputs caches the input files of the request in the file proxy,
+// the highest priority first, and sends the outcome of every input as it is
+// fetched. The inputs keep being fetched when the client goes away.
+func (s *server) PrefetchInputs(in *pb.PrefetchRequest, stream pb.TasksRequest_PrefetchInputsServer) error {
+	if serverProxy == nil {
+		return errors.New("file caching is disabled on this agent")
+	}
+
+	inputs := append([]*pb.PrefetchInput{}, in.Inputs...)
+	sort.SliceStable(inputs, func(i, j int) bool { return inputs[i].Priority > inputs[j].Priority })
+
+	concurrency := s.providerConfig.FilePrefetchConcurrency
+	if in.Concurrency > 0 && int(in.Concurrency) < concurrency {
+		concurrency = int(in.Concurrency)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	queue := make(chan *pb.PrefetchInput, len(inputs))
+	for _, input := range inputs {
+		queue <- input
+	}
+	close(queue)
+
+	// buffered for every input, so the workers never block once the
+	// client is gone
+	results := make(chan *pb.PrefetchProgress, len(inputs))
+	for i := 0; i < concurrency && i < len(inputs); i++ {
+		go func() {
+			for input := range queue {
+				results <- prefetchInput(input.Url)
+			}
+		}()
+	}
+
+	var completed, failed int64
+	for range inputs {
+		select {
+		case <-stream.Context().Done():
+			log.Printf("[Prefetch] client went away after %d/%d inputs, the others are still fetched\n", completed+failed, len(inputs))
+			return stream.Context().Err()
+		case progress := <-results:
+			if len(progress.Error) > 0 {
+				failed++
+			} else {
+				completed++
+			}
+			progress.Completed, progress.Failed, progress.Total = completed, failed, int64(len(inputs))
+			if err := stream.Send(progress); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Printf("[Prefetch] fetched %d inputs, %d failed\n", completed, failed)
+	return nil
+}
+
+func prefetchInput(rawURL string) *pb.PrefetchProgress {
+	start := time.Now()
+	size, cached, err := serverProxy.Prefetch(context.Background(), rawURL)
+	progress := &pb.PrefetchProgress{
+		Url:                rawURL,
+		Cached:             cached,
+		SizeBytes:          size,
+		DurationNanoSecond: time.Since(start).Nanoseconds(),
+	}
+	if err != nil {
+		log.Printf("[Prefetch] cannot fetch %s: %s\n", rawURL, err)
+		progress.Error = err.Error()
+	}
+	return progress
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"faasd-agent/pkg/config"
+	pb "faasd-agent/proto/agent"
+
+	"google.golang.org/grpc"
+)
+
+// prefetchStream records the progress sent by PrefetchInputs.
+type prefetchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.PrefetchProgress
+}
+
+func (s *prefetchStream) Context() context.Context { return s.ctx }
+
+func (s *prefetchStream) Send(progress *pb.PrefetchProgress) error {
+	s.sent = append(s.sent, progress)
+	return nil
+}
+
+// recordingOrigin answers every path with its path after delay, it records
+// the order of the requests and the most requests it served at once.
+type recordingOrigin struct {
+	delay time.Duration
+
+	mutex       sync.Mutex
+	paths       []string
+	inFlight    int
+	maxInFlight int
+}
+
+func (o *recordingOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mutex.Lock()
+	o.paths = append(o.paths, r.URL.Path)
+	o.inFlight++
+	if o.inFlight > o.maxInFlight {
+		o.maxInFlight = o.inFlight
+	}
+	o.mutex.Unlock()
+
+	time.Sleep(o.delay)
+
+	o.mutex.Lock()
+	o.inFlight--
+	o.mutex.Unlock()
+	if r.URL.Path == "/missing" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte(r.URL.Path))
+}
+
+// setupTestPrefetch serves the input URLs http://files.test/* from origin
+// for the duration of the test, prefetching up to concurrency files at once.
+func setupTestPrefetch(t *testing.T, origin http.Handler, concurrency int) *server {
+	ts := httptest.NewServer(origin)
+	t.Cleanup(ts.Close)
+
+	previous := serverProxy
+	t.Cleanup(func() { serverProxy = previous })
+	serverProxy = newTestProxy(t, ts, false)
+	serverProxy.prefetchSlots = make(chan struct{}, concurrency)
+	return &server{providerConfig: &config.ProviderConfig{FilePrefetchConcurrency: concurrency}}
+}
+
+func prefetchInputs(urls []string, priorities []int32) []*pb.PrefetchInput {
+	inputs := make([]*pb.PrefetchInput, len(urls))
+	for i, u := range urls {
+		inputs[i] = &pb.PrefetchInput{Url: u, Priority: priorities[i]}
+	}
+	return inputs
+}
+
+func Test_PrefetchInputsByPriority(t *testing.T) {
+	origin := &recordingOrigin{}
+	s := setupTestPrefetch(t, origin, 4)
+
+	request := &pb.PrefetchRequest{
+		Inputs: prefetchInputs(
+			[]string{"http://files.test/low", "http://files.test/high", "http://files.test/mid-1", "http://files.test/mid-2"},
+			[]int32{0, 10, 5, 5}),
+		Concurrency: 1,
+	}
+	stream := &prefetchStream{ctx: context.Background()}
+	if err := s.PrefetchInputs(request, stream); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	expected := []string{"/high", "/mid-1", "/mid-2", "/low"}
+	if !reflect.DeepEqual(origin.paths, expected) {
+		t.Fatalf("expected the inputs to be fetched in order %v, got %v", expected, origin.paths)
+	}
+}
+
+func Test_PrefetchInputsConcurrency(t *testing.T) {
+	urls := []string{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		urls = append(urls, "http://files.test/"+name)
+	}
+	priorities := make([]int32, len(urls))
+
+	scenarios := []struct {
+		name        string
+		agent       int
+		request     int32
+		maxInFlight int
+	}{
+		{name: "agent limit", agent: 2, request: 0, maxInFlight: 2},
+		{name: "request limit below the agent one", agent: 4, request: 3, maxInFlight: 3},
+		{name: "request limit above the agent one", agent: 2, request: 8, maxInFlight: 2},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			origin := &recordingOrigin{delay: 20 * time.Millisecond}
+			s := setupTestPrefetch(t, origin, scenario.agent)
+
+			request := &pb.PrefetchRequest{Inputs: prefetchInputs(urls, priorities), Concurrency: scenario.request}
+			if err := s.PrefetchInputs(request, &prefetchStream{ctx: context.Background()}); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if origin.maxInFlight != scenario.maxInFlight {
+				t.Fatalf("expected at most %d files fetched at once, got %d", scenario.maxInFlight, origin.maxInFlight)
+			}
+		})
+	}
+}
+
+func Test_PrefetchInputsProgress(t *testing.T) {
+	s := setupTestPrefetch(t, &recordingOrigin{}, 2)
+
+	request := &pb.PrefetchRequest{Inputs: prefetchInputs(
+		[]string{"http://files.test/a", "http://files.test/missing", "http://other.test/b", "http://files.test/a"},
+		[]int32{3, 2, 1, 0}),
+		Concurrency: 1,
+	}
+	stream := &prefetchStream{ctx: context.Background()}
+	if err := s.PrefetchInputs(request, stream); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if len(stream.sent) != 4 {
+		t.Fatalf("expected a progress per input, got %d", len(stream.sent))
+	}
+	for i, progress := range stream.sent {
+		if progress.Total != 4 || progress.Completed+progress.Failed != int64(i+1) {
+			t.Fatalf("progress %d: expected %d of 4 inputs done, got %+v", i, i+1, progress)
+		}
+	}
+	last := stream.sent[3]
+	if last.Completed != 2 || last.Failed != 2 {
+		t.Fatalf("expected 2 completed and 2 failed inputs, got %+v", last)
+	}
+	if first := stream.sent[0]; first.Cached || first.SizeBytes != int64(len("/a")) || len(first.Error) > 0 {
+		t.Fatalf("expected /a to be fetched, got %+v", first)
+	}
+	if again := stream.sent[3]; again.Url != "http://files.test/a" || !again.Cached {
+		t.Fatalf("expected /a to be cached already the second time, got %+v", again)
+	}
+}
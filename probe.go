@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// ProbeCache reports for every request hash, and every request, whether its
+// result is cached on this agent, without executing anything. In FileCaching
+// mode the hashes are input file URLs and the file proxy cache is probed instead.
+func (s *server) ProbeCache(ctx context.Context, in *pb.CacheProbeRequest) (*pb.CacheProbeResponse, error) {
+	now := time.Now()
+	res := &pb.CacheProbeResponse{Results: make([]*pb.CacheProbeResult, 0, len(in.RequestHashes)+len(in.Requests))}
+	for _, reqHash := range in.RequestHashes {
+		res.Results = append(res.Results, probeResult(reqHash, now))
+	}
+	for _, task := range in.Requests {
+		reqHash, err := s.taskCacheKey(task)
+		if err != nil {
+			log.Printf("[Probe] cannot compute the cache key of %s: %s\n", task.FunctionName, err)
+			res.Results = append(res.Results, &pb.CacheProbeResult{})
+			continue
+		}
+		res.Results = append(res.Results, probeResult(reqHash, now))
+	}
+	return res, nil
+}
+
+// taskCacheKey returns the cache key of the request of the task, as computed
+// by TaskAssign.
+func (s *server) taskCacheKey(task *pb.TaskRequest) (string, error) {
+	req, err := unserializeReq(task.SerializeReq)
+	if err != nil {
+		return "", err
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	policy, _ := s.cachePolicy(task.FunctionName)
+	baseKey := s.cacheKey(task.FunctionName, req, task.ExteraPath, body, policy)
+	if FileCaching {
+		return baseKey, nil
+	}
+	return s.varyKey(peekResult, baseKey, task.FunctionName, req, task.ExteraPath, body, policy), nil
+}
+
+func probeResult(reqHash string, now time.Time) *pb.CacheProbeResult {
+	result := &pb.CacheProbeResult{RequestHash: reqHash}
+	if entry, found := probeEntry(reqHash); found {
+		result.Hit = true
+		result.Size = entry.logicalSize()
+		result.AgeNanoSecond = now.Sub(entry.createdAt).Nanoseconds()
+		result.ExecutionTimeNanoSecond = entry.executionTime.Nanoseconds()
+	}
+	return result
+}
+
+// probeEntry looks reqHash up without changing its recency in the cache,
+// expired entries are not found.
+func probeEntry(reqHash string) (*cacheEntry, bool) {
+	var value interface{}
+	var found bool
+	if FileCaching {
+		if key, ok := serverProxy.Origins.Key(reqHash); ok {
+			value, found = serverProxy.peek(key)
+		}
+	} else {
+		value, found = peekResult(reqHash)
+	}
+	if !found {
+		return nil, false
+	}
+	entry := value.(*cacheEntry)
+	// a vary marker has no response, see varyKey
+	if entry.expired(time.Now()) || len(entry.vary) > 0 {
+		return nil, false
+	}
+	return entry, true
+}
@@ -18,6 +18,19 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TasksRequestClient interface {
 	TaskAssign(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	TaskStream(ctx context.Context, opts ...grpc.CallOption) (TasksRequest_TaskStreamClient, error)
+	ProbeCache(ctx context.Context, in *CacheProbeRequest, opts ...grpc.CallOption) (*CacheProbeResponse, error)
+	GetAgentStatus(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (*AgentStatus, error)
+	WatchAgentStatus(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (TasksRequest_WatchAgentStatusClient, error)
+	Deploy(ctx context.Context, in *FunctionDeployment, opts ...grpc.CallOption) (*FunctionDeployResponse, error)
+	Update(ctx context.Context, in *FunctionDeployment, opts ...grpc.CallOption) (*FunctionDeployResponse, error)
+	Delete(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*FunctionDeployResponse, error)
+	ListFunctions(ctx context.Context, in *ListFunctionsRequest, opts ...grpc.CallOption) (*ListFunctionsResponse, error)
+	GetFunction(ctx context.Context, in *GetFunctionRequest, opts ...grpc.CallOption) (*FunctionStatus, error)
+	ExportCache(ctx context.Context, in *ExportCacheRequest, opts ...grpc.CallOption) (TasksRequest_ExportCacheClient, error)
+	ImportCache(ctx context.Context, opts ...grpc.CallOption) (TasksRequest_ImportCacheClient, error)
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error)
+	PrefetchInputs(ctx context.Context, in *PrefetchRequest, opts ...grpc.CallOption) (TasksRequest_PrefetchInputsClient, error)
 }
 
 type tasksRequestClient struct {
@@ -37,11 +50,257 @@ func (c *tasksRequestClient) TaskAssign(ctx context.Context, in *TaskRequest, op
 	return out, nil
 }
 
+func (c *tasksRequestClient) TaskStream(ctx context.Context, opts ...grpc.CallOption) (TasksRequest_TaskStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TasksRequest_serviceDesc.Streams[0], "/agent.TasksRequest/TaskStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tasksRequestTaskStreamClient{stream}
+	return x, nil
+}
+
+type TasksRequest_TaskStreamClient interface {
+	Send(*TaskStreamRequest) error
+	Recv() (*TaskStreamResponse, error)
+	grpc.ClientStream
+}
+
+type tasksRequestTaskStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *tasksRequestTaskStreamClient) Send(m *TaskStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *tasksRequestTaskStreamClient) Recv() (*TaskStreamResponse, error) {
+	m := new(TaskStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tasksRequestClient) ProbeCache(ctx context.Context, in *CacheProbeRequest, opts ...grpc.CallOption) (*CacheProbeResponse, error) {
+	out := new(CacheProbeResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/ProbeCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) GetAgentStatus(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (*AgentStatus, error) {
+	out := new(AgentStatus)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/GetAgentStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) WatchAgentStatus(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (TasksRequest_WatchAgentStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TasksRequest_serviceDesc.Streams[1], "/agent.TasksRequest/WatchAgentStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tasksRequestWatchAgentStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TasksRequest_WatchAgentStatusClient interface {
+	Recv() (*AgentStatus, error)
+	grpc.ClientStream
+}
+
+type tasksRequestWatchAgentStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *tasksRequestWatchAgentStatusClient) Recv() (*AgentStatus, error) {
+	m := new(AgentStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tasksRequestClient) Deploy(ctx context.Context, in *FunctionDeployment, opts ...grpc.CallOption) (*FunctionDeployResponse, error) {
+	out := new(FunctionDeployResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/Deploy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) Update(ctx context.Context, in *FunctionDeployment, opts ...grpc.CallOption) (*FunctionDeployResponse, error) {
+	out := new(FunctionDeployResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) Delete(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*FunctionDeployResponse, error) {
+	out := new(FunctionDeployResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) ListFunctions(ctx context.Context, in *ListFunctionsRequest, opts ...grpc.CallOption) (*ListFunctionsResponse, error) {
+	out := new(ListFunctionsResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/ListFunctions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) GetFunction(ctx context.Context, in *GetFunctionRequest, opts ...grpc.CallOption) (*FunctionStatus, error) {
+	out := new(FunctionStatus)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/GetFunction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) ExportCache(ctx context.Context, in *ExportCacheRequest, opts ...grpc.CallOption) (TasksRequest_ExportCacheClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TasksRequest_serviceDesc.Streams[2], "/agent.TasksRequest/ExportCache", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tasksRequestExportCacheClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TasksRequest_ExportCacheClient interface {
+	Recv() (*CacheSnapshotEntry, error)
+	grpc.ClientStream
+}
+
+type tasksRequestExportCacheClient struct {
+	grpc.ClientStream
+}
+
+func (x *tasksRequestExportCacheClient) Recv() (*CacheSnapshotEntry, error) {
+	m := new(CacheSnapshotEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tasksRequestClient) ImportCache(ctx context.Context, opts ...grpc.CallOption) (TasksRequest_ImportCacheClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TasksRequest_serviceDesc.Streams[3], "/agent.TasksRequest/ImportCache", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tasksRequestImportCacheClient{stream}
+	return x, nil
+}
+
+type TasksRequest_ImportCacheClient interface {
+	Send(*CacheSnapshotEntry) error
+	CloseAndRecv() (*ImportCacheResponse, error)
+	grpc.ClientStream
+}
+
+type tasksRequestImportCacheClient struct {
+	grpc.ClientStream
+}
+
+func (x *tasksRequestImportCacheClient) Send(m *CacheSnapshotEntry) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *tasksRequestImportCacheClient) CloseAndRecv() (*ImportCacheResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportCacheResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tasksRequestClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error) {
+	out := new(InvalidateCacheResponse)
+	err := c.cc.Invoke(ctx, "/agent.TasksRequest/InvalidateCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksRequestClient) PrefetchInputs(ctx context.Context, in *PrefetchRequest, opts ...grpc.CallOption) (TasksRequest_PrefetchInputsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TasksRequest_serviceDesc.Streams[4], "/agent.TasksRequest/PrefetchInputs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tasksRequestPrefetchInputsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TasksRequest_PrefetchInputsClient interface {
+	Recv() (*PrefetchProgress, error)
+	grpc.ClientStream
+}
+
+type tasksRequestPrefetchInputsClient struct {
+	grpc.ClientStream
+}
+
+func (x *tasksRequestPrefetchInputsClient) Recv() (*PrefetchProgress, error) {
+	m := new(PrefetchProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TasksRequestServer is the server API for TasksRequest service.
 // All implementations must embed UnimplementedTasksRequestServer
 // for forward compatibility
 type TasksRequestServer interface {
 	TaskAssign(context.Context, *TaskRequest) (*TaskResponse, error)
+	TaskStream(TasksRequest_TaskStreamServer) error
+	ProbeCache(context.Context, *CacheProbeRequest) (*CacheProbeResponse, error)
+	GetAgentStatus(context.Context, *AgentStatusRequest) (*AgentStatus, error)
+	WatchAgentStatus(*AgentStatusRequest, TasksRequest_WatchAgentStatusServer) error
+	Deploy(context.Context, *FunctionDeployment) (*FunctionDeployResponse, error)
+	Update(context.Context, *FunctionDeployment) (*FunctionDeployResponse, error)
+	Delete(context.Context, *DeleteFunctionRequest) (*FunctionDeployResponse, error)
+	ListFunctions(context.Context, *ListFunctionsRequest) (*ListFunctionsResponse, error)
+	GetFunction(context.Context, *GetFunctionRequest) (*FunctionStatus, error)
+	ExportCache(*ExportCacheRequest, TasksRequest_ExportCacheServer) error
+	ImportCache(TasksRequest_ImportCacheServer) error
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error)
+	PrefetchInputs(*PrefetchRequest, TasksRequest_PrefetchInputsServer) error
 	mustEmbedUnimplementedTasksRequestServer()
 }
 
@@ -52,6 +311,45 @@ type UnimplementedTasksRequestServer struct {
 func (UnimplementedTasksRequestServer) TaskAssign(context.Context, *TaskRequest) (*TaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TaskAssign not implemented")
 }
+func (UnimplementedTasksRequestServer) TaskStream(TasksRequest_TaskStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method TaskStream not implemented")
+}
+func (UnimplementedTasksRequestServer) ProbeCache(context.Context, *CacheProbeRequest) (*CacheProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProbeCache not implemented")
+}
+func (UnimplementedTasksRequestServer) GetAgentStatus(context.Context, *AgentStatusRequest) (*AgentStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentStatus not implemented")
+}
+func (UnimplementedTasksRequestServer) WatchAgentStatus(*AgentStatusRequest, TasksRequest_WatchAgentStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAgentStatus not implemented")
+}
+func (UnimplementedTasksRequestServer) Deploy(context.Context, *FunctionDeployment) (*FunctionDeployResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deploy not implemented")
+}
+func (UnimplementedTasksRequestServer) Update(context.Context, *FunctionDeployment) (*FunctionDeployResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedTasksRequestServer) Delete(context.Context, *DeleteFunctionRequest) (*FunctionDeployResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedTasksRequestServer) ListFunctions(context.Context, *ListFunctionsRequest) (*ListFunctionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFunctions not implemented")
+}
+func (UnimplementedTasksRequestServer) GetFunction(context.Context, *GetFunctionRequest) (*FunctionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFunction not implemented")
+}
+func (UnimplementedTasksRequestServer) ExportCache(*ExportCacheRequest, TasksRequest_ExportCacheServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCache not implemented")
+}
+func (UnimplementedTasksRequestServer) ImportCache(TasksRequest_ImportCacheServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportCache not implemented")
+}
+func (UnimplementedTasksRequestServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
+func (UnimplementedTasksRequestServer) PrefetchInputs(*PrefetchRequest, TasksRequest_PrefetchInputsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PrefetchInputs not implemented")
+}
 func (UnimplementedTasksRequestServer) mustEmbedUnimplementedTasksRequestServer() {}
 
 // UnsafeTasksRequestServer may be embedded to opt out of forward compatibility for this service.
@@ -83,6 +381,265 @@ func _TasksRequest_TaskAssign_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TasksRequest_TaskStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TasksRequestServer).TaskStream(&tasksRequestTaskStreamServer{stream})
+}
+
+type TasksRequest_TaskStreamServer interface {
+	Send(*TaskStreamResponse) error
+	Recv() (*TaskStreamRequest, error)
+	grpc.ServerStream
+}
+
+type tasksRequestTaskStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *tasksRequestTaskStreamServer) Send(m *TaskStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *tasksRequestTaskStreamServer) Recv() (*TaskStreamRequest, error) {
+	m := new(TaskStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TasksRequest_ProbeCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).ProbeCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/ProbeCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).ProbeCache(ctx, req.(*CacheProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_GetAgentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).GetAgentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/GetAgentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).GetAgentStatus(ctx, req.(*AgentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_WatchAgentStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AgentStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TasksRequestServer).WatchAgentStatus(m, &tasksRequestWatchAgentStatusServer{stream})
+}
+
+type TasksRequest_WatchAgentStatusServer interface {
+	Send(*AgentStatus) error
+	grpc.ServerStream
+}
+
+type tasksRequestWatchAgentStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *tasksRequestWatchAgentStatusServer) Send(m *AgentStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TasksRequest_Deploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunctionDeployment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).Deploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/Deploy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).Deploy(ctx, req.(*FunctionDeployment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunctionDeployment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).Update(ctx, req.(*FunctionDeployment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).Delete(ctx, req.(*DeleteFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_ListFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFunctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).ListFunctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/ListFunctions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).ListFunctions(ctx, req.(*ListFunctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_GetFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).GetFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/GetFunction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).GetFunction(ctx, req.(*GetFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_ExportCache_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCacheRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TasksRequestServer).ExportCache(m, &tasksRequestExportCacheServer{stream})
+}
+
+type TasksRequest_ExportCacheServer interface {
+	Send(*CacheSnapshotEntry) error
+	grpc.ServerStream
+}
+
+type tasksRequestExportCacheServer struct {
+	grpc.ServerStream
+}
+
+func (x *tasksRequestExportCacheServer) Send(m *CacheSnapshotEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TasksRequest_ImportCache_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TasksRequestServer).ImportCache(&tasksRequestImportCacheServer{stream})
+}
+
+type TasksRequest_ImportCacheServer interface {
+	SendAndClose(*ImportCacheResponse) error
+	Recv() (*CacheSnapshotEntry, error)
+	grpc.ServerStream
+}
+
+type tasksRequestImportCacheServer struct {
+	grpc.ServerStream
+}
+
+func (x *tasksRequestImportCacheServer) SendAndClose(m *ImportCacheResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *tasksRequestImportCacheServer) Recv() (*CacheSnapshotEntry, error) {
+	m := new(CacheSnapshotEntry)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TasksRequest_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksRequestServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.TasksRequest/InvalidateCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksRequestServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksRequest_PrefetchInputs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrefetchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TasksRequestServer).PrefetchInputs(m, &tasksRequestPrefetchInputsServer{stream})
+}
+
+type TasksRequest_PrefetchInputsServer interface {
+	Send(*PrefetchProgress) error
+	grpc.ServerStream
+}
+
+type tasksRequestPrefetchInputsServer struct {
+	grpc.ServerStream
+}
+
+func (x *tasksRequestPrefetchInputsServer) Send(m *PrefetchProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _TasksRequest_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "agent.TasksRequest",
 	HandlerType: (*TasksRequestServer)(nil),
@@ -91,7 +648,66 @@ var _TasksRequest_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TaskAssign",
 			Handler:    _TasksRequest_TaskAssign_Handler,
 		},
+		{
+			MethodName: "ProbeCache",
+			Handler:    _TasksRequest_ProbeCache_Handler,
+		},
+		{
+			MethodName: "GetAgentStatus",
+			Handler:    _TasksRequest_GetAgentStatus_Handler,
+		},
+		{
+			MethodName: "Deploy",
+			Handler:    _TasksRequest_Deploy_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _TasksRequest_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _TasksRequest_Delete_Handler,
+		},
+		{
+			MethodName: "ListFunctions",
+			Handler:    _TasksRequest_ListFunctions_Handler,
+		},
+		{
+			MethodName: "GetFunction",
+			Handler:    _TasksRequest_GetFunction_Handler,
+		},
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _TasksRequest_InvalidateCache_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TaskStream",
+			Handler:       _TasksRequest_TaskStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchAgentStatus",
+			Handler:       _TasksRequest_WatchAgentStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportCache",
+			Handler:       _TasksRequest_ExportCache_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportCache",
+			Handler:       _TasksRequest_ImportCache_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PrefetchInputs",
+			Handler:       _TasksRequest_PrefetchInputs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "agent.proto",
 }
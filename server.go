@@ -1,36 +1,84 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
-	"path/filepath"
+	"path"
 	"strconv"
 	"sync/atomic"
+	"time"
+
+	"faasd-agent/pkg/cache"
+	"faasd-agent/pkg/config"
+	"faasd-agent/pkg/filestore"
+	"faasd-agent/pkg/httpcache"
+	"faasd-agent/pkg/origins"
 
 	"github.com/fanap-infra/log"
 	"github.com/gin-gonic/gin"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 type Server struct {
-	Engine   *gin.Engine
-	Port     string
-	Cache    *lru.Cache
+	Engine *gin.Engine
+	Port   string
+	// BaseURL is the URL functions reach the proxy at
+	BaseURL string
+	// Cache is the memory tier of the input files, nil when disabled
+	Cache cache.Cache
+	// Store is the disk store of the input files, nil when disabled
+	Store    *filestore.Store
 	CacheHit uint64
-}
-
-type FileName struct {
-	fileName string `uri:"fileName" binding:"required"`
+	// DiskHit counts the files served from Store
+	DiskHit uint64
+	// Revalidations counts the stale files the origin confirmed unchanged,
+	// StaleServed those served because the origin failed
+	Revalidations uint64
+	StaleServed   uint64
+	// prefetchSlots bounds the input files prefetched at the same time
+	prefetchSlots chan struct{}
+	// Origins map the served paths to the upstreams files are fetched from
+	Origins origins.Rules
 }
 
 var serverProxy *Server
 
+// maxMemoryFileFraction is the fraction of the memory tier of the file
+// proxy the largest file kept in it may take, when there is a disk store.
+const maxMemoryFileFraction = 4
+
+const (
+	// originFetchTimeout bounds a fetch of a file from its origin, body
+	// included, so a hung origin does not hold a prefetch slot for good
+	originFetchTimeout = 5 * time.Minute
+	// originHeaderTimeout bounds the wait for the response headers of an origin
+	originHeaderTimeout = 30 * time.Second
+)
+
+// originClient fetches the files from their origins.
+var originClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: originHeaderTimeout,
+	},
+}
+
 func (s *Server) Run() {
 	addr := "0.0.0.0:" + s.Port
 	fmt.Printf("Proxy runs on address: %s \n ", addr)
 
-	s.Engine.GET("/assets/images/:fileName", s.NetworkRequests)
+	s.Engine.GET("/*path", s.NetworkRequests)
 
 	srv := &http.Server{
 		Addr:    addr,
@@ -58,68 +106,449 @@ func (s *Server) Run() {
 	}()
 }
 
+// NetworkRequests serves the input file of the URL http://host/path under
+// /host/path, from the cache or from the upstream of the origin rule it
+// matches. Cached files are served with Range and conditional requests
+// support, and revalidated with the origin once stale. A miss is streamed to
+// the function while it is cached.
 func (s *Server) NetworkRequests(c *gin.Context) {
-	var fileName FileName
-	// if err := c.ShouldBindUri(&fileName); err != nil {
-	// 	fmt.Println("Error it is not valid request, err:", err.Error())
-	// 	return
-	// }
-	fileName.fileName = c.Param("fileName")
-
-	fmt.Println("******* fileName Request:", fileName.fileName)
-	// fileName.fileName = "I1.jpg"
-	// director := func(req *http.Request) {
-	// 	r := c.Request
-	// 	req.URL.Scheme = "http"
-	// 	req.URL.Host = "mvatandoosts.ir"
-	// 	req.URL.Path = fmt.Sprintf("/assets/images/%s", fileName.fileName)
-	// 	req.RequestURI = req.URL.Path
-	// 	req.Header["my-header"] = []string{r.Header.Get("my-header")}
-	// 	delete(req.Header, "My-Header")
-	// 	fmt.Println("req.URL.Path:", req.URL.Path)
-	// 	fmt.Println("req.URL.String():", req.URL.String())
-	// }
+	u, rule, err := s.Origins.FromProxyPath(c.Request.URL.Path, c.Request.URL.RawQuery)
+	if err != nil {
+		fmt.Println("not an input file, err:", err.Error())
+		c.Status(http.StatusNotFound)
+		return
+	}
+	key := origins.CacheKey(u)
+	name := path.Base(u.Path)
 
-	res, found := s.Cache.Get(fileName.fileName)
+	fmt.Println("******* fileName Request:", key)
 
+	content, origin, found := s.open(key)
 	if found {
+		if closer, ok := content.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
+	if found && !origin.Stale(time.Now()) {
 		atomic.AddUint64(&s.CacheHit, 1)
-		fmt.Printf("find in cache, fileName: %v, CacheHit: %v \n", fileName.fileName, s.CacheHit)
+		fmt.Printf("find in cache, fileName: %v, CacheHit: %v \n", key, s.CacheHit)
 
-		c.Header("Content-Description", "File Transfer")
-		c.Header("Content-Transfer-Encoding", "binary")
-		c.Header("Content-Disposition", "attachment; filename="+filepath.Base(fileName.fileName))
-		c.Header("Content-Type", "application/octet-stream")
-		c.Data(http.StatusOK, "application/octet-stream", res.([]byte))
+		serveContent(c, name, origin, content)
 		return
 	}
 
-	fmt.Println("does not find in cache, fileName:", fileName.fileName)
-	resp, err := http.Get("http://mvatandoosts.ir/assets/images/" + fileName.fileName)
-	if err != nil {
+	var cached *filestore.Origin
+	if found {
+		cached = &origin
+		fmt.Println("revalidating stale file, fileName:", key)
+	} else {
+		fmt.Println("does not find in cache, fileName:", key)
+	}
+	// the fetch outlives a client which goes away, the file is still cached
+	ctx, cancel := context.WithTimeout(context.Background(), originFetchTimeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := fetch(ctx, rule.UpstreamURL(u), cached)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if err == nil {
+			err = fmt.Errorf("status %v", resp.StatusCode)
+			resp.Body.Close()
+		}
+		if found && rule.StaleIfError > 0 && time.Now().Before(origin.ExpiresAt.Add(rule.StaleIfError)) {
+			atomic.AddUint64(&s.StaleServed, 1)
+			fmt.Printf("origin failed, serving stale file, fileName: %v, err: %v \n", key, err)
+			serveContent(c, name, origin, content)
+			return
+		}
 		fmt.Println("can not get request value, err:", err.Error())
+		c.Status(http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		origin = s.revalidated(key, origin, resp, rule)
+		atomic.AddUint64(&s.Revalidations, 1)
+		fmt.Printf("origin did not modify the file, fileName: %v, Revalidations: %v \n", key, s.Revalidations)
+		serveContent(c, name, origin, content)
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("origin answered %v, fileName: %v \n", resp.StatusCode, key)
+		if found {
+			s.remove(key)
+		}
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, nil)
+		return
+	}
+
+	origin = originOf(resp, rule, time.Now())
+
+	// a range of a file which is not cached yet is served once it is stored,
+	// without disk store the whole file is sent
+	if len(c.GetHeader("Range")) > 0 && s.Store != nil {
+		if err := s.fill(key, origin, resp.Body, nil, time.Since(start)); err != nil {
+			fmt.Println("can not cache file, err:", err.Error())
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		if content, origin, found := s.open(key); found {
+			if closer, ok := content.(io.Closer); ok {
+				defer closer.Close()
+			}
+			serveContent(c, name, origin, content)
+			return
+		}
+		c.Status(http.StatusBadGateway)
 		return
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	setFileHeaders(c, name, origin)
+	if resp.ContentLength >= 0 {
+		c.Header("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	c.Status(http.StatusOK)
+	client := &stickyWriter{w: c.Writer}
+	if err := s.fill(key, origin, resp.Body, client, time.Since(start)); err != nil {
+		fmt.Println("can not cache file, err:", err.Error())
+	}
+}
+
+// fetch gets the file at rawURL from its origin, conditionally on the
+// validators of the cached file unless it is nil. Reading the body fails once
+// ctx is done.
+func fetch(ctx context.Context, rawURL string, cached *filestore.Origin) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		fmt.Println("can not get read value, err:", err.Error())
+		return nil, err
+	}
+	if cached != nil {
+		if len(cached.ETag) > 0 {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if len(cached.LastModified) > 0 {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	return originClient.Do(req)
+}
+
+// originOf describes the origin response resp of a file matching rule.
+func originOf(resp *http.Response, rule origins.Rule, now time.Time) filestore.Origin {
+	origin := filestore.Origin{
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	ttl, explicit := httpcache.Lifetime(resp, now)
+	if !explicit {
+		ttl = rule.MaxAge
+	}
+	if explicit || ttl > 0 {
+		origin.ExpiresAt = now.Add(ttl)
 	}
+	return origin
+}
 
-	s.Cache.Add(fileName.fileName, body)
+// revalidated renews the freshness of the cached file of key after the
+// origin answered resp, a 304, and returns its updated origin.
+func (s *Server) revalidated(key string, cached filestore.Origin, resp *http.Response, rule origins.Rule) filestore.Origin {
+	origin := originOf(resp, rule, time.Now())
+	// a 304 carries the validators, not the representation headers
+	origin.ContentType = cached.ContentType
+	if len(origin.ETag) == 0 {
+		origin.ETag = cached.ETag
+	}
+	if len(origin.LastModified) == 0 {
+		origin.LastModified = cached.LastModified
+	}
 
+	if s.Store != nil {
+		s.Store.Revalidated(key, origin)
+	}
+	if s.Cache != nil {
+		if res, found := s.Cache.Peek(key); found {
+			entry := *res.(*cacheEntry)
+			entry.origin = origin
+			s.Cache.Add(key, &entry, entry.size())
+		}
+	}
+	return origin
+}
+
+// Prefetch caches the input file of the URL rawURL unless it is cached and
+// fresh already, which cached reports, and returns its size. It gives up,
+// waiting for a prefetch slot or fetching, once ctx is done, and the fetch
+// gets originFetchTimeout from when it holds a slot.
+func (s *Server) Prefetch(ctx context.Context, rawURL string) (size int64, cached bool, err error) {
+	u, rule, ok := s.Origins.Resolve(rawURL)
+	if !ok {
+		return 0, false, fmt.Errorf("no origin rule matches %s", rawURL)
+	}
+	key := origins.CacheKey(u)
+
+	select {
+	case s.prefetchSlots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, false, ctx.Err()
+	}
+	defer func() { <-s.prefetchSlots }()
+	// not counting the wait for the slot, the queued fetches would time out
+	ctx, cancel := context.WithTimeout(ctx, originFetchTimeout)
+	defer cancel()
+
+	var stale *filestore.Origin
+	if res, found := s.peek(key); found {
+		entry := res.(*cacheEntry)
+		if !entry.origin.Stale(time.Now()) {
+			return entry.logicalSize(), true, nil
+		}
+		stale = &entry.origin
+	}
+
+	start := time.Now()
+	resp, err := fetch(ctx, rule.UpstreamURL(u), stale)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case stale != nil && resp.StatusCode == http.StatusNotModified:
+		s.revalidated(key, *stale, resp, rule)
+		atomic.AddUint64(&s.Revalidations, 1)
+	case resp.StatusCode == http.StatusOK:
+		if err := s.fill(key, originOf(resp, rule, time.Now()), resp.Body, nil, time.Since(start)); err != nil {
+			return 0, false, err
+		}
+	default:
+		return 0, false, fmt.Errorf("origin answered %v", resp.StatusCode)
+	}
+
+	res, found := s.peek(key)
+	if !found {
+		return 0, false, fmt.Errorf("file of %s does not fit in the cache", rawURL)
+	}
+	return res.(*cacheEntry).logicalSize(), false, nil
+}
+
+// remove removes the file of key from both tiers.
+func (s *Server) remove(key string) {
+	if s.Cache != nil {
+		s.Cache.Remove(key)
+	}
+	if s.Store != nil {
+		s.Store.Remove(key)
+	}
+}
+
+// fill reads the file of key from body into the disk store and the memory
+// tier, writing it to client as well unless it is nil.
+func (s *Server) fill(key string, origin filestore.Origin, body io.Reader, client io.Writer, fetchTime time.Duration) error {
+	var sinks []io.Writer
+	if client != nil {
+		sinks = append(sinks, client)
+	}
+
+	var stored *stickyWriter
+	if s.Store != nil {
+		w, err := s.Store.Create(key, origin)
+		if err != nil {
+			fmt.Println("can not store file, err:", err.Error())
+		} else {
+			stored = &stickyWriter{w: w}
+			sinks = append(sinks, stored)
+		}
+	}
+
+	var memory *limitedBuffer
+	if s.Cache != nil {
+		memory = &limitedBuffer{limit: s.memoryLimit()}
+		sinks = append(sinks, memory)
+	}
+
+	_, err := io.Copy(io.MultiWriter(sinks...), body)
+	if stored != nil {
+		w := stored.w.(*filestore.Writer)
+		if err == nil && stored.err == nil {
+			_, err = w.Commit()
+		} else {
+			w.Abort()
+			if err == nil {
+				err = stored.err
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if memory != nil && !memory.overflow {
+		s.Cache.Add(key, &cacheEntry{response: memory.Bytes(), createdAt: time.Now(), executionTime: fetchTime, origin: origin},
+			int64(memory.Len()))
+	}
+	return nil
+}
+
+// open returns the content of the file of key from the memory tier or,
+// promoting it to memory when it fits, from the disk store. The content is
+// an io.Closer the caller must close when it is read from disk.
+func (s *Server) open(key string) (io.ReadSeeker, filestore.Origin, bool) {
+	if s.Cache != nil {
+		if res, found := s.Cache.Get(key); found {
+			entry := res.(*cacheEntry)
+			return bytes.NewReader(entry.response), entry.origin, true
+		}
+	}
+	if s.Store == nil {
+		return nil, filestore.Origin{}, false
+	}
+
+	f, meta, found := s.Store.Open(key)
+	if !found {
+		return nil, filestore.Origin{}, false
+	}
+	atomic.AddUint64(&s.DiskHit, 1)
+	if s.Cache == nil || meta.Size > s.memoryLimit() {
+		return f, meta.Origin, true
+	}
+
+	defer f.Close()
+	body, err := ioutil.ReadAll(f)
+	if err != nil {
+		fmt.Println("can not read stored file, err:", err.Error())
+		return nil, filestore.Origin{}, false
+	}
+	s.Cache.Add(key, &cacheEntry{response: body, createdAt: meta.StoredAt, origin: meta.Origin}, int64(len(body)))
+	return bytes.NewReader(body), meta.Origin, true
+}
+
+// peek returns the file of key from the memory tier, or its description
+// from the disk store, without recording the access.
+func (s *Server) peek(key string) (interface{}, bool) {
+	if s.Cache != nil {
+		if res, found := s.Cache.Peek(key); found {
+			return res, true
+		}
+	}
+	if s.Store == nil {
+		return nil, false
+	}
+	meta, found := s.Store.Peek(key)
+	if !found {
+		return nil, false
+	}
+	return &cacheEntry{createdAt: meta.StoredAt, rawSize: meta.Size, origin: meta.Origin}, true
+}
+
+// memoryLimit is the size of the largest file kept in the memory tier, the
+// memory tier is kept for small, hot files when there is a disk store.
+func (s *Server) memoryLimit() int64 {
+	if s.Store != nil {
+		return s.Cache.Capacity() / maxMemoryFileFraction
+	}
+	return s.Cache.Capacity()
+}
+
+func setFileHeaders(c *gin.Context, name string, origin filestore.Origin) {
+	contentType := origin.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Transfer-Encoding", "binary")
-	c.Header("Content-Disposition", "attachment; filename="+filepath.Base(fileName.fileName))
-	c.Header("Content-Type", "application/octet-stream")
-	c.Data(http.StatusOK, "application/octet-stream", body)
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Header("Content-Type", contentType)
+	if len(origin.ETag) > 0 {
+		c.Header("ETag", origin.ETag)
+	}
+	if len(origin.LastModified) > 0 {
+		c.Header("Last-Modified", origin.LastModified)
+	}
+}
+
+// serveContent serves a cached file, answering Range, If-Range and the
+// conditional requests with the ETag and Last-Modified of the origin.
+func serveContent(c *gin.Context, name string, origin filestore.Origin, content io.ReadSeeker) {
+	setFileHeaders(c, name, origin)
+	modTime, _ := http.ParseTime(origin.LastModified)
+	http.ServeContent(c.Writer, c.Request, name, modTime, content)
+}
+
+// stickyWriter writes to w until a write fails, then discards what follows
+// and keeps the error, so a client going away does not stop a file from
+// being cached, nor a failing cache from serving it.
+type stickyWriter struct {
+	w   io.Writer
+	err error
 }
 
-func RunProxy(port string) {
+func (s *stickyWriter) Write(p []byte) (int, error) {
+	if s.err == nil {
+		_, s.err = s.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// limitedBuffer buffers up to limit bytes, it overflows beyond.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if !b.overflow && int64(b.Len()+len(p)) > b.limit {
+		b.overflow = true
+		b.Reset()
+	}
+	if b.overflow {
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// RunProxy starts the file caching proxy on port, configured by providerConfig.
+func RunProxy(port string, providerConfig *config.ProviderConfig) error {
 	// GetSizeOfFiles()
-	Cache, _ = lru.New(FileCacheSize)
-	serverProxy = &Server{Engine: gin.New(), Port: port, Cache: Cache, CacheHit: 0}
+	rules, err := origins.Parse(providerConfig.FileOrigins)
+	if err != nil {
+		return err
+	}
+	serverProxy = &Server{Engine: gin.New(), Port: port, CacheHit: 0, Origins: rules}
+	serverProxy.BaseURL = proxyBaseURL(providerConfig.FileProxyAddress, port)
+	concurrency := providerConfig.FilePrefetchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	serverProxy.prefetchSlots = make(chan struct{}, concurrency)
+
+	if providerConfig.FileCacheMemorySize > 0 {
+		serverProxy.Cache, err = cache.New(cache.PolicyLRU, providerConfig.FileCacheMemorySize, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if len(providerConfig.FileCachePath) > 0 {
+		serverProxy.Store, err = filestore.Open(providerConfig.FileCachePath, providerConfig.FileCacheSize, providerConfig.FileCachePolicy)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("File store: %s, size: %d bytes, policy: %s, reloaded %d files \n",
+			providerConfig.FileCachePath, providerConfig.FileCacheSize, providerConfig.FileCachePolicy, serverProxy.Store.Len())
+	}
+
 	serverProxy.Run()
+	return nil
+}
+
+// proxyBaseURL returns the URL of the proxy listening on port advertised at
+// address, which is a host or a host:port.
+func proxyBaseURL(address, port string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return "http://" + address
+	}
+	return "http://" + net.JoinHostPort(address, port)
 }
 
 func GetSizeOfFiles() {
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"faasd-agent/pkg/cache"
+	"faasd-agent/pkg/filestore"
+	"faasd-agent/pkg/origins"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestProxy returns a file proxy serving the input URLs http://files.test/*
+// from origin, with a disk store when withStore is set.
+func newTestProxy(t *testing.T, origin *httptest.Server, withStore bool) *Server {
+	gin.SetMode(gin.TestMode)
+	rules, err := origins.Parse([]string{"files.test/=" + origin.URL + "/"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	s := &Server{Engine: gin.New(), Port: "0", Origins: rules, prefetchSlots: make(chan struct{}, 4)}
+	if s.Cache, err = cache.New(cache.PolicyLRU, 1<<20, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if withStore {
+		if s.Store, err = filestore.Open(t.TempDir(), 1<<20, cache.PolicyLRU); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		t.Cleanup(func() { s.Store.Close() })
+	}
+	s.Engine.GET("/*path", s.NetworkRequests)
+	return s
+}
+
+// hangingOrigin returns an origin which never answers under /hang, and
+// answers the other paths with their path.
+func hangingOrigin(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hang" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	t.Cleanup(func() {
+		close(release)
+		origin.Close()
+	})
+	return origin
+}
+
+func Test_PrefetchGivesUpOnHungOrigin(t *testing.T) {
+	s := newTestProxy(t, hangingOrigin(t), false)
+	s.prefetchSlots = make(chan struct{}, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := s.Prefetch(ctx, "http://files.test/hang"); err == nil {
+		t.Fatalf("expected an error for an origin which does not answer")
+	}
+
+	size, cached, err := s.Prefetch(context.Background(), "http://files.test/a.txt")
+	if err != nil {
+		t.Fatalf("expected the prefetch slot to be released, got %s", err)
+	}
+	if size != int64(len("/a.txt")) || cached {
+		t.Fatalf("expected a fetched file of %d bytes, got %d bytes, cached: %v", len("/a.txt"), size, cached)
+	}
+}
+
+func Test_PrefetchWaitsForSlotUntilContextDone(t *testing.T) {
+	s := newTestProxy(t, hangingOrigin(t), false)
+	s.prefetchSlots = make(chan struct{}, 1)
+	s.prefetchSlots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := s.Prefetch(ctx, "http://files.test/a.txt"); err != context.DeadlineExceeded {
+		t.Fatalf("expected %s while every slot is taken, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+const (
+	testFileBody         = "0123456789abcdefghij"
+	testFileETag         = `"v1"`
+	testFileLastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+)
+
+// fileOrigin serves testFileBody with validators, fresh for an hour, and
+// counts the requests it answers.
+type fileOrigin struct {
+	requests int64
+}
+
+func (o *fileOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&o.requests, 1)
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("ETag", testFileETag)
+	w.Header().Set("Last-Modified", testFileLastModified)
+	w.Header().Set("Cache-Control", "max-age=3600")
+	w.Write([]byte(testFileBody))
+}
+
+func proxyGet(s *Server, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_ProxyServesAndCachesFiles(t *testing.T) {
+	for _, withStore := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disk store: %v", withStore), func(t *testing.T) {
+			origin := &fileOrigin{}
+			ts := httptest.NewServer(origin)
+			defer ts.Close()
+			s := newTestProxy(t, ts, withStore)
+
+			for i := 0; i < 2; i++ {
+				rec := proxyGet(s, "/files.test/cats/1.jpg", nil)
+				if rec.Code != http.StatusOK || rec.Body.String() != testFileBody {
+					t.Fatalf("request %d: expected the file, got %d %q", i, rec.Code, rec.Body.String())
+				}
+				headers := map[string]string{"Content-Type": "image/jpeg", "ETag": testFileETag, "Last-Modified": testFileLastModified}
+				for name, value := range headers {
+					if got := rec.Header().Get(name); got != value {
+						t.Fatalf("request %d: expected %s %s, got %q", i, name, value, got)
+					}
+				}
+			}
+			if origin.requests != 1 {
+				t.Fatalf("expected the origin to be requested once, got %d", origin.requests)
+			}
+		})
+	}
+}
+
+func Test_ProxyServesRangesOfCachedFiles(t *testing.T) {
+	for _, withStore := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disk store: %v", withStore), func(t *testing.T) {
+			origin := &fileOrigin{}
+			ts := httptest.NewServer(origin)
+			defer ts.Close()
+			s := newTestProxy(t, ts, withStore)
+			proxyGet(s, "/files.test/1.jpg", nil)
+			if withStore {
+				// served from the disk store
+				s.Cache.Purge()
+			}
+
+			scenarios := []struct {
+				name    string
+				headers map[string]string
+				code    int
+				body    string
+			}{
+				{name: "range", headers: map[string]string{"Range": "bytes=2-5"}, code: http.StatusPartialContent, body: "2345"},
+				{name: "if-range matching", headers: map[string]string{"Range": "bytes=10-", "If-Range": testFileETag}, code: http.StatusPartialContent, body: "abcdefghij"},
+				{name: "if-range not matching", headers: map[string]string{"Range": "bytes=10-", "If-Range": `"v0"`}, code: http.StatusOK, body: testFileBody},
+				{name: "if-none-match", headers: map[string]string{"If-None-Match": testFileETag}, code: http.StatusNotModified, body: ""},
+			}
+			for _, scenario := range scenarios {
+				rec := proxyGet(s, "/files.test/1.jpg", scenario.headers)
+				if rec.Code != scenario.code || rec.Body.String() != scenario.body {
+					t.Fatalf("%s: expected %d %q, got %d %q", scenario.name, scenario.code, scenario.body, rec.Code, rec.Body.String())
+				}
+			}
+			if rec := proxyGet(s, "/files.test/1.jpg", map[string]string{"Range": "bytes=2-5"}); rec.Header().Get("Content-Range") != "bytes 2-5/20" {
+				t.Fatalf("expected Content-Range bytes 2-5/20, got %q", rec.Header().Get("Content-Range"))
+			}
+			if origin.requests != 1 {
+				t.Fatalf("expected the ranges to be served from the cache, got %d origin requests", origin.requests)
+			}
+		})
+	}
+}
+
+func Test_ProxyServesRangeOfMissFromDiskStore(t *testing.T) {
+	ts := httptest.NewServer(&fileOrigin{})
+	defer ts.Close()
+	s := newTestProxy(t, ts, true)
+
+	rec := proxyGet(s, "/files.test/1.jpg", map[string]string{"Range": "bytes=0-3"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "0123" {
+		t.Fatalf("expected the range of the file, got %d %q", rec.Code, rec.Body.String())
+	}
+	if _, found := s.Store.Peek("files.test/1.jpg"); !found {
+		t.Fatalf("expected the whole file to be stored")
+	}
+}
+
+func Test_ProxyStreamsMissWhileCachingIt(t *testing.T) {
+	// larger than the buffers between the origin and the client
+	first, second := bytes.Repeat([]byte("a"), 64*1024), bytes.Repeat([]byte("b"), 32*1024)
+	release := make(chan struct{})
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(first)
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write(second)
+	}))
+	defer origin.Close()
+
+	for _, withStore := range []bool{false, true} {
+		s := newTestProxy(t, origin, withStore)
+		proxy := httptest.NewServer(s.Engine)
+
+		resp, err := http.Get(proxy.URL + "/files.test/1.jpg")
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		// the first part reaches the client while the origin holds the rest
+		received := make([]byte, len(first))
+		if _, err := io.ReadFull(resp.Body, received); err != nil || !bytes.Equal(received, first) {
+			t.Fatalf("disk store: %v: expected the first part to be streamed, got %v", withStore, err)
+		}
+		release <- struct{}{}
+		rest, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || !bytes.Equal(rest, second) {
+			t.Fatalf("disk store: %v: expected the rest of the file, got %d bytes, %v", withStore, len(rest), err)
+		}
+		proxy.Close()
+
+		content, cachedOrigin, found := s.open("files.test/1.jpg")
+		if !found {
+			t.Fatalf("disk store: %v: expected the streamed file to be cached", withStore)
+		}
+		cached, _ := ioutil.ReadAll(content)
+		if closer, ok := content.(io.Closer); ok {
+			closer.Close()
+		}
+		if !bytes.Equal(cached, append(first, second...)) || cachedOrigin.ContentType != "image/jpeg" {
+			t.Fatalf("disk store: %v: expected the whole file cached as image/jpeg, got %d bytes as %q", withStore, len(cached), cachedOrigin.ContentType)
+		}
+	}
+}
+
+// originResponse is an answer of a scriptedOrigin, fresh for maxAge.
+type originResponse struct {
+	status int
+	body   string
+	etag   string
+	maxAge int
+}
+
+// scriptedOrigin answers the requests with its responses in turn, the last
+// one repeatedly, and records the If-None-Match of every request.
+type scriptedOrigin struct {
+	mutex       sync.Mutex
+	responses   []originResponse
+	ifNoneMatch []string
+}
+
+func (o *scriptedOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mutex.Lock()
+	o.ifNoneMatch = append(o.ifNoneMatch, r.Header.Get("If-None-Match"))
+	res := o.responses[0]
+	if len(o.responses) > 1 {
+		o.responses = o.responses[1:]
+	}
+	o.mutex.Unlock()
+
+	if len(res.etag) > 0 {
+		w.Header().Set("ETag", res.etag)
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", res.maxAge))
+	if res.status != http.StatusOK {
+		w.WriteHeader(res.status)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Write([]byte(res.body))
+}
+
+func (o *scriptedOrigin) requests() []string {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return append([]string{}, o.ifNoneMatch...)
+}
+
+// newRevalidationProxy returns a file proxy of origin whose files are stale
+// once served, the rule of the origin ending with options.
+func newRevalidationProxy(t *testing.T, origin *scriptedOrigin, withStore bool, options string) *Server {
+	ts := httptest.NewServer(origin)
+	t.Cleanup(ts.Close)
+	s := newTestProxy(t, ts, withStore)
+	rules, err := origins.Parse([]string{"files.test/=" + ts.URL + "/" + options})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	s.Origins = rules
+	return s
+}
+
+func Test_ProxyRevalidatesStaleFiles(t *testing.T) {
+	for _, withStore := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disk store: %v", withStore), func(t *testing.T) {
+			origin := &scriptedOrigin{responses: []originResponse{
+				{status: http.StatusOK, body: "v1", etag: `"v1"`, maxAge: 0},
+				{status: http.StatusNotModified, etag: `"v1"`, maxAge: 3600},
+			}}
+			s := newRevalidationProxy(t, origin, withStore, "")
+
+			for i := 0; i < 3; i++ {
+				rec := proxyGet(s, "/files.test/1.jpg", nil)
+				if rec.Code != http.StatusOK || rec.Body.String() != "v1" || rec.Header().Get("Content-Type") != "image/jpeg" {
+					t.Fatalf("request %d: expected the cached file, got %d %q", i, rec.Code, rec.Body.String())
+				}
+			}
+			// the 304 renewed the freshness of the file, the third request is a hit
+			if requests := origin.requests(); !reflect.DeepEqual(requests, []string{"", `"v1"`}) {
+				t.Fatalf("expected a request and a conditional one, got If-None-Match %q", requests)
+			}
+			if s.Revalidations != 1 {
+				t.Fatalf("expected 1 revalidation, got %d", s.Revalidations)
+			}
+			if _, cachedOrigin, found := s.open("files.test/1.jpg"); !found || cachedOrigin.Stale(time.Now()) {
+				t.Fatalf("expected the revalidated file to be stored fresh")
+			}
+		})
+	}
+}
+
+func Test_ProxyServesStaleFilesOnOriginError(t *testing.T) {
+	scenarios := []struct {
+		name    string
+		options string
+		code    int
+		body    string
+		stale   uint64
+	}{
+		{name: "within stale-if-error", options: ";stale-if-error=1h", code: http.StatusOK, body: "v1", stale: 1},
+		{name: "without stale-if-error", options: "", code: http.StatusBadGateway, body: "", stale: 0},
+	}
+	for _, withStore := range []bool{false, true} {
+		for _, scenario := range scenarios {
+			t.Run(fmt.Sprintf("%s, disk store: %v", scenario.name, withStore), func(t *testing.T) {
+				origin := &scriptedOrigin{responses: []originResponse{
+					{status: http.StatusOK, body: "v1", etag: `"v1"`, maxAge: 0},
+					{status: http.StatusInternalServerError},
+				}}
+				s := newRevalidationProxy(t, origin, withStore, scenario.options)
+				proxyGet(s, "/files.test/1.jpg", nil)
+
+				rec := proxyGet(s, "/files.test/1.jpg", nil)
+				if rec.Code != scenario.code || rec.Body.String() != scenario.body {
+					t.Fatalf("expected %d %q, got %d %q", scenario.code, scenario.body, rec.Code, rec.Body.String())
+				}
+				if s.StaleServed != scenario.stale {
+					t.Fatalf("expected the stale file to be served %d times, got %d", scenario.stale, s.StaleServed)
+				}
+			})
+		}
+	}
+}
+
+func Test_ProxyReplacesChangedFiles(t *testing.T) {
+	for _, withStore := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disk store: %v", withStore), func(t *testing.T) {
+			origin := &scriptedOrigin{responses: []originResponse{
+				{status: http.StatusOK, body: "v1", etag: `"v1"`, maxAge: 0},
+				{status: http.StatusOK, body: "v2-changed", etag: `"v2"`, maxAge: 3600},
+			}}
+			s := newRevalidationProxy(t, origin, withStore, "")
+			proxyGet(s, "/files.test/1.jpg", nil)
+
+			rec := proxyGet(s, "/files.test/1.jpg", nil)
+			if rec.Code != http.StatusOK || rec.Body.String() != "v2-changed" || rec.Header().Get("ETag") != `"v2"` {
+				t.Fatalf("expected the changed file, got %d %q, ETag %q", rec.Code, rec.Body.String(), rec.Header().Get("ETag"))
+			}
+
+			content, cachedOrigin, found := s.open("files.test/1.jpg")
+			if !found {
+				t.Fatalf("expected the changed file to be cached")
+			}
+			cached, _ := ioutil.ReadAll(content)
+			if closer, ok := content.(io.Closer); ok {
+				closer.Close()
+			}
+			if string(cached) != "v2-changed" || cachedOrigin.ETag != `"v2"` {
+				t.Fatalf("expected the changed file and its validators cached, got %q, ETag %q", cached, cachedOrigin.ETag)
+			}
+			// served from the cache with the new validators
+			if rec := proxyGet(s, "/files.test/1.jpg", map[string]string{"If-None-Match": `"v2"`}); rec.Code != http.StatusNotModified {
+				t.Fatalf("expected the new ETag to match, got %d", rec.Code)
+			}
+			if requests := len(origin.requests()); requests != 2 {
+				t.Fatalf("expected 2 origin requests, got %d", requests)
+			}
+		})
+	}
+}
+
+func Test_PrefetchRevalidatesStaleFiles(t *testing.T) {
+	origin := &scriptedOrigin{responses: []originResponse{
+		{status: http.StatusOK, body: "v1", etag: `"v1"`, maxAge: 0},
+		{status: http.StatusNotModified, etag: `"v1"`, maxAge: 3600},
+	}}
+	s := newRevalidationProxy(t, origin, false, "")
+
+	for i := 0; i < 3; i++ {
+		size, _, err := s.Prefetch(context.Background(), "http://files.test/1.jpg")
+		if err != nil || size != int64(len("v1")) {
+			t.Fatalf("prefetch %d: expected the file of %d bytes, got %d bytes, %v", i, len("v1"), size, err)
+		}
+	}
+	if requests := origin.requests(); !reflect.DeepEqual(requests, []string{"", `"v1"`}) {
+		t.Fatalf("expected a request and a conditional one, got If-None-Match %q", requests)
+	}
+	if s.Revalidations != 1 {
+		t.Fatalf("expected 1 revalidation, got %d", s.Revalidations)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"faasd-agent/pkg/snapshot"
+	pb "faasd-agent/proto/agent"
+
+	"google.golang.org/grpc"
+)
+
+// ExportCache streams the cached results, the disk tier first, then memory,
+// each from the least to the most recently used.
+func (s *server) ExportCache(in *pb.ExportCacheRequest, stream pb.TasksRequest_ExportCacheServer) error {
+	exported := 0
+	inMemory := make(map[string]bool)
+	memoryKeys := Cache.Keys()
+	for _, key := range memoryKeys {
+		inMemory[key] = true
+	}
+
+	if diskCache != nil {
+		for _, key := range diskCache.Keys() {
+			if inMemory[key] {
+				continue
+			}
+			if entry, found := loadResult(key, false); found {
+				sent, err := sendSnapshotEntry(stream, key, entry, in.IncludeValues)
+				if err != nil {
+					return err
+				}
+				if sent {
+					exported++
+				}
+			}
+		}
+	}
+
+	for _, key := range memoryKeys {
+		if value, found := Cache.Peek(key); found {
+			sent, err := sendSnapshotEntry(stream, key, value.(*cacheEntry), in.IncludeValues)
+			if err != nil {
+				return err
+			}
+			if sent {
+				exported++
+			}
+		}
+	}
+
+	log.Printf("[Snapshot] exported %d cache entries, values: %v\n", exported, in.IncludeValues)
+	return nil
+}
+
+// ImportCache adds the received results to the cache, in the order they are
+// received. Entries without value and ones past their stale window are
+// skipped.
+func (s *server) ImportCache(stream pb.TasksRequest_ImportCacheServer) error {
+	res := &pb.ImportCacheResponse{}
+	now := time.Now()
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			log.Printf("[Snapshot] imported %d cache entries, skipped %d\n", res.Imported, res.Skipped)
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+
+		entry, err := cacheEntryFromSnapshot(in)
+		if err != nil {
+			return err
+		}
+		if (len(entry.response) == 0 && len(entry.vary) == 0) || entry.removable(now) {
+			res.Skipped++
+			continue
+		}
+
+		mutex.Lock()
+		added := addResult(in.Key, entry)
+		mutex.Unlock()
+		flushPending()
+		if added || diskCache != nil {
+			res.Imported++
+		} else {
+			res.Skipped++
+		}
+	}
+}
+
+// sendSnapshotEntry sends the entry of key on stream, it reports false when
+// the value of the entry cannot be decompressed and it is skipped.
+func sendSnapshotEntry(stream pb.TasksRequest_ExportCacheServer, key string, e *cacheEntry, includeValue bool) (bool, error) {
+	entry, err := snapshotEntry(key, e, includeValue)
+	if err != nil {
+		log.Printf("[Snapshot] skipping %s: %s\n", key, err)
+		return false, nil
+	}
+	return true, stream.Send(entry)
+}
+
+// snapshotEntry returns the entry of key with its value decompressed, so
+// snapshots do not depend on the compression of the agent.
+func snapshotEntry(key string, e *cacheEntry, includeValue bool) (*pb.CacheSnapshotEntry, error) {
+	entry := &pb.CacheSnapshotEntry{
+		Key:                     key,
+		FunctionName:            e.functionName,
+		Size:                    e.logicalSize(),
+		CreatedAtNanoSecond:     e.createdAt.UnixNano(),
+		ExecutionTimeNanoSecond: e.executionTime.Nanoseconds(),
+		Hits:                    atomic.LoadUint64(&e.hits),
+		Vary:                    e.vary,
+		Image:                   e.image,
+	}
+	if !e.expiresAt.IsZero() {
+		entry.ExpiresAtNanoSecond = e.expiresAt.UnixNano()
+	}
+	if !e.staleUntil.IsZero() {
+		entry.StaleUntilNanoSecond = e.staleUntil.UnixNano()
+	}
+	if includeValue {
+		value, err := e.body()
+		if err != nil {
+			return nil, err
+		}
+		entry.Value = value
+	}
+	return entry, nil
+}
+
+// cacheEntryFromSnapshot returns the entry of in with its value compressed
+// by the compressor of the agent.
+func cacheEntryFromSnapshot(in *pb.CacheSnapshotEntry) (*cacheEntry, error) {
+	entry := &cacheEntry{
+		createdAt:     time.Unix(0, in.CreatedAtNanoSecond),
+		executionTime: time.Duration(in.ExecutionTimeNanoSecond),
+		vary:          in.Vary,
+		functionName:  in.FunctionName,
+		image:         in.Image,
+		hits:          in.Hits,
+	}
+	if in.ExpiresAtNanoSecond != 0 {
+		entry.expiresAt = time.Unix(0, in.ExpiresAtNanoSecond)
+	}
+	if in.StaleUntilNanoSecond != 0 {
+		entry.staleUntil = time.Unix(0, in.StaleUntilNanoSecond)
+	}
+	if err := entry.setResponse(in.Value); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// runSnapshotCommand runs the snapshot subcommand of the agent binary:
+//
+//	faasd-agent snapshot export -addr localhost:50051 -out cache.snapshot [-values]
+//	faasd-agent snapshot import -addr localhost:50051 -in cache.snapshot
+func runSnapshotCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected export or import")
+	}
+
+	flags := flag.NewFlagSet("snapshot "+args[0], flag.ExitOnError)
+	addr := flags.String("addr", "localhost"+port, "address of the agent")
+	switch args[0] {
+	case "export":
+		out := flags.String("out", "", "file the snapshot is written to")
+		values := flags.Bool("values", false, "include the cached responses")
+		flags.Parse(args[1:])
+		if len(*out) == 0 {
+			return fmt.Errorf("-out is required")
+		}
+		return exportSnapshot(*addr, *out, *values)
+	case "import":
+		in := flags.String("in", "", "file the snapshot is read from")
+		flags.Parse(args[1:])
+		if len(*in) == 0 {
+			return fmt.Errorf("-in is required")
+		}
+		return importSnapshot(*addr, *in)
+	}
+	return fmt.Errorf("unknown snapshot command %q, expected export or import", args[0])
+}
+
+func exportSnapshot(addr, path string, values bool) error {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewTasksRequestClient(conn).ExportCache(context.Background(), &pb.ExportCacheRequest{IncludeValues: values})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	buf := bufio.NewWriter(file)
+
+	w, err := snapshot.NewWriter(buf, snapshot.Header{CreatedAtNanoSecond: time.Now().UnixNano(), Values: values})
+	if err != nil {
+		return err
+	}
+	count := 0
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := w.Write(fileEntry(in)); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	log.Printf("exported %d cache entries from %s to %s\n", count, addr, path)
+	return file.Close()
+}
+
+func importSnapshot(addr, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r, header, err := snapshot.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return err
+	}
+	if !header.Values {
+		log.Printf("warning: %s has no values, the cache entries will be skipped\n", path)
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewTasksRequestClient(conn).ImportCache(context.Background())
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(protoEntry(e)); err != nil {
+			return err
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	log.Printf("imported %d cache entries from %s to %s, skipped %d\n", res.Imported, path, addr, res.Skipped)
+	return nil
+}
+
+func fileEntry(in *pb.CacheSnapshotEntry) snapshot.Entry {
+	return snapshot.Entry{
+		Key:                     in.Key,
+		FunctionName:            in.FunctionName,
+		Size:                    in.Size,
+		CreatedAtNanoSecond:     in.CreatedAtNanoSecond,
+		ExpiresAtNanoSecond:     in.ExpiresAtNanoSecond,
+		ExecutionTimeNanoSecond: in.ExecutionTimeNanoSecond,
+		Hits:                    in.Hits,
+		Vary:                    in.Vary,
+		Value:                   in.Value,
+		Image:                   in.Image,
+		StaleUntilNanoSecond:    in.StaleUntilNanoSecond,
+	}
+}
+
+func protoEntry(e snapshot.Entry) *pb.CacheSnapshotEntry {
+	return &pb.CacheSnapshotEntry{
+		Key:                     e.Key,
+		FunctionName:            e.FunctionName,
+		Size:                    e.Size,
+		CreatedAtNanoSecond:     e.CreatedAtNanoSecond,
+		ExpiresAtNanoSecond:     e.ExpiresAtNanoSecond,
+		ExecutionTimeNanoSecond: e.ExecutionTimeNanoSecond,
+		Hits:                    e.Hits,
+		Vary:                    e.Vary,
+		Value:                   e.Value,
+		Image:                   e.Image,
+		StaleUntilNanoSecond:    e.StaleUntilNanoSecond,
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_SnapshotEntryRoundTrip(t *testing.T) {
+	setupTestCache(t, false)
+
+	now := time.Now()
+	entry := testEntry(t, "fn", "fn:1", "result")
+	entry.hits = 4
+	entry.expiresAt = now.Add(-time.Minute)
+	entry.staleUntil = now.Add(time.Minute)
+
+	exported, err := snapshotEntry("k", entry, true)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	imported, err := cacheEntryFromSnapshot(protoEntry(fileEntry(exported)))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if imported.hits != 4 {
+		t.Fatalf("expected 4 hits, got %d", imported.hits)
+	}
+	if !imported.expiresAt.Equal(entry.expiresAt) || !imported.staleUntil.Equal(entry.staleUntil) {
+		t.Fatalf("expected to expire at %s and be stale until %s, got %s and %s",
+			entry.expiresAt, entry.staleUntil, imported.expiresAt, imported.staleUntil)
+	}
+	if body, _ := imported.body(); string(body) != "result" || imported.image != "fn:1" {
+		t.Fatalf("expected the result of fn:1, got %q of %q", body, imported.image)
+	}
+	// expired, still served stale
+	if !imported.expired(now) || imported.removable(now) {
+		t.Fatalf("expected the imported result to be kept for its stale window")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// revalidateTimeout bounds a background refresh of a stale result.
+const revalidateTimeout = time.Minute
+
+// staleHits counts the expired results served while they were revalidated,
+// staleErrors those served because the function failed.
+var staleHits uint64
+var staleErrors uint64
+
+// revalidate refreshes the stale result of key in the background through the
+// same path as a miss, the refreshes of key are coalesced with each other and
+// with the misses in flight.
+func revalidate(functionName, key string, execute func(ctx context.Context) (*invocation, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+	defer cancel()
+
+	if _, _, err := coalesce(ctx, key, func() (*invocation, error) { return execute(ctx) }); err != nil {
+		log.Printf("[Cache] cannot revalidate the result of %s: %s\n", functionName, err)
+	}
+}
+
+// serveStale answers a task whose execution failed with the expired result
+// of the function, cause is nil when the function answered with a server error.
+func serveStale(functionName string, entry *cacheEntry, cause error) (*pb.TaskResponse, error) {
+	response, err := entry.body()
+	if err != nil {
+		log.Printf("[Cache] cannot decompress the stale result of %s: %s\n", functionName, err)
+		if cause != nil {
+			return nil, cause
+		}
+		return nil, err
+	}
+	atomic.AddUint64(&staleErrors, 1)
+	atomic.AddUint64(&entry.hits, 1)
+	log.Printf("[Cache] %s failed, serving a result expired %s ago: %v\n",
+		functionName, time.Since(entry.expiresAt).Round(time.Millisecond), cause)
+	return &pb.TaskResponse{Message: "OK", Response: response}, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"faasd-agent/pkg/config"
+	"faasd-agent/pkg/handlers"
+	pb "faasd-agent/proto/agent"
+)
+
+// testResolver resolves every function to the same address.
+type testResolver struct {
+	address url.URL
+}
+
+func (r testResolver) Resolve(functionName string) (url.URL, handlers.Function, error) {
+	return r.address, handlers.Function{}, nil
+}
+
+func (r testResolver) Lookup(functionName string) (handlers.Function, error) {
+	return handlers.Function{}, nil
+}
+
+func (r testResolver) Invalidate(functionName string) {}
+
+func (r testResolver) Watch(ctx context.Context) {}
+
+// newTestAgent returns an agent sending the tasks of every function to
+// function, with an empty result cache.
+func newTestAgent(t *testing.T, function http.HandlerFunc) *server {
+	setupTestCache(t, false)
+	ts := httptest.NewServer(function)
+	t.Cleanup(ts.Close)
+	address, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	return &server{providerConfig: &config.ProviderConfig{}, proxyClient: &http.Client{}, invokeResolver: testResolver{*address}}
+}
+
+func assignTestTask(ctx context.Context, s *server, policy handlers.CachePolicy, image string) (*pb.TaskResponse, error) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	return s.assignTask(ctx, &pb.TaskRequest{FunctionName: "fn"}, req, []byte("input"), policy, image)
+}
+
+// storeStaleResult caches the result "stale" of the test task of image,
+// expired for expiredFor, and returns its key.
+func storeStaleResult(t *testing.T, s *server, policy handlers.CachePolicy, image string, expiredFor time.Duration) string {
+	key := s.cacheKey("fn", httptest.NewRequest(http.MethodPost, "/", nil), "", []byte("input"), policy)
+	entry := testEntry(t, "fn", image, "stale")
+	entry.expiresAt = time.Now().Add(-expiredFor)
+	entry.staleUntil = entry.expiresAt.Add(policy.StaleWindow())
+	addResult(key, entry)
+	return key
+}
+
+// hangingFunction answers no task, it returns once the task is canceled.
+func hangingFunction(w http.ResponseWriter, r *http.Request) {
+	// the cancellation is only noticed once the request is read
+	ioutil.ReadAll(r.Body)
+	<-r.Context().Done()
+}
+
+func cachedBody(key string) string {
+	value, found := Cache.Peek(key)
+	if !found {
+		return ""
+	}
+	body, _ := value.(*cacheEntry).body()
+	return string(body)
+}
+
+func Test_AssignTaskServesStaleWhileRevalidating(t *testing.T) {
+	var requests int64
+	release := make(chan struct{})
+	s := newTestAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte("fresh"))
+	})
+	policy := handlers.CachePolicy{Enabled: true, TTL: time.Hour, StaleWhileRevalidate: time.Hour}
+	key := storeStaleResult(t, s, policy, "", time.Minute)
+
+	// answered while the function holds the refresh
+	for i := 0; i < 3; i++ {
+		res, err := assignTestTask(context.Background(), s, policy, "")
+		if err != nil || string(res.Response) != "stale" {
+			t.Fatalf("task %d: expected the stale result at once, got %v", i, err)
+		}
+	}
+	for atomic.LoadInt64(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(joinDelay)
+	close(release)
+
+	// joins the refresh in flight to wait for it
+	_, shared, err := coalesce(context.Background(), key, func() (*invocation, error) {
+		return nil, errors.New("no refresh in flight")
+	})
+	if err != nil || !shared {
+		t.Fatalf("expected the refresh to be in flight, got %v", err)
+	}
+	if body := cachedBody(key); !strings.Contains(body, "fresh") {
+		t.Fatalf("expected the stale result to be refreshed, got %q", body)
+	}
+	if requests := atomic.LoadInt64(&requests); requests != 1 {
+		t.Fatalf("expected a single background refresh, got %d", requests)
+	}
+}
+
+func Test_AssignTaskServesStaleIfError(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		function http.HandlerFunc
+	}{
+		{
+			name: "server error",
+			function: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{name: "timeout", function: hangingFunction},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			s := newTestAgent(t, scenario.function)
+			policy := handlers.CachePolicy{Enabled: true, TTL: time.Hour, StaleIfError: time.Hour}
+			key := storeStaleResult(t, s, policy, "", time.Minute)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			res, err := assignTestTask(ctx, s, policy, "")
+			if err != nil || string(res.Response) != "stale" {
+				t.Fatalf("expected the stale result, got %v", err)
+			}
+			if body := cachedBody(key); body != "stale" {
+				t.Fatalf("expected the failure not to replace the stale result, got %q", body)
+			}
+		})
+	}
+}
+
+func Test_AssignTaskRemovesResultPastStaleWindows(t *testing.T) {
+	s := newTestAgent(t, hangingFunction)
+	policy := handlers.CachePolicy{Enabled: true, TTL: time.Hour, StaleWhileRevalidate: time.Minute, StaleIfError: time.Minute}
+	key := storeStaleResult(t, s, policy, "", 2*time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if res, err := assignTestTask(ctx, s, policy, ""); err == nil {
+		t.Fatalf("expected the function error past the stale windows, got %q", res.Response)
+	}
+	if _, found := Cache.Peek(key); found {
+		t.Fatalf("expected the result past its stale windows to be removed")
+	}
+}
+
+func Test_AssignTaskNeverServesStaleResultOfOlderImage(t *testing.T) {
+	s := newTestAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	policy := handlers.CachePolicy{Enabled: true, TTL: time.Hour, StaleIfError: time.Hour}
+	key := storeStaleResult(t, s, policy, "fn:1", time.Minute)
+
+	res, err := assignTestTask(context.Background(), s, policy, "fn:2")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if string(res.Response) == "stale" {
+		t.Fatalf("expected the result of the previous image not to be served")
+	}
+	if body := cachedBody(key); body == "stale" {
+		t.Fatalf("expected the result of the previous image to be invalidated")
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"faasd-agent/pkg/cache"
+	"faasd-agent/pkg/metrics"
+	pb "faasd-agent/proto/agent"
+)
+
+const (
+	defaultStatusInterval = time.Second
+	minStatusInterval     = 100 * time.Millisecond
+	// hostSampleInterval is how often the host statistics are sampled, the
+	// reported CPU usage is the one of the latest interval
+	hostSampleInterval = time.Second
+)
+
+var hostSampler metrics.HostSampler
+
+// GetAgentStatus reports the current load of the agent so the scheduler can
+// balance tasks between agents.
+func (s *server) GetAgentStatus(ctx context.Context, in *pb.AgentStatusRequest) (*pb.AgentStatus, error) {
+	return s.agentStatus(), nil
+}
+
+// WatchAgentStatus sends the agent status periodically until the client goes away.
+func (s *server) WatchAgentStatus(in *pb.AgentStatusRequest, stream pb.TasksRequest_WatchAgentStatusServer) error {
+	interval := time.Duration(in.IntervalNanoSecond)
+	if interval <= 0 {
+		interval = defaultStatusInterval
+	}
+	if interval < minStatusInterval {
+		interval = minStatusInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(s.agentStatus()); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *server) agentStatus() *pb.AgentStatus {
+	status := &pb.AgentStatus{
+		TimeNanoSecond: time.Now().UnixNano(),
+		InFlightTasks:  tracker.InFlight(),
+		QueueDepth:     atomic.LoadInt64(&queueDepth),
+		Cache:          s.cacheStatus(),
+	}
+
+	for _, f := range tracker.Snapshot() {
+		divergenceRate := 0.0
+		if f.Verifications > 0 {
+			divergenceRate = float64(f.Divergences) / float64(f.Verifications)
+		}
+		status.Functions = append(status.Functions, &pb.FunctionLoad{
+			FunctionName:           f.Name,
+			InFlight:               f.InFlight,
+			Samples:                int64(f.Samples),
+			P50NanoSecond:          f.P50.Nanoseconds(),
+			P90NanoSecond:          f.P90.Nanoseconds(),
+			P99NanoSecond:          f.P99.Nanoseconds(),
+			ColdStarts:             f.ColdStarts,
+			ColdStartP50NanoSecond: f.ColdStartP50.Nanoseconds(),
+			ColdStartP99NanoSecond: f.ColdStartP99.Nanoseconds(),
+			CacheHits:              f.CacheHits,
+			CacheSavedSeconds:      f.CacheSaved.Seconds(),
+			Verifications:          f.Verifications,
+			Divergences:            f.Divergences,
+			DivergenceRate:         divergenceRate,
+			CacheDisabled:          cacheDisabled(f.Name),
+		})
+	}
+
+	host, err := hostSampler.Latest()
+	if err != nil {
+		log.Printf("failed to sample host stats: %v", err)
+	}
+	status.Host = &pb.HostStatus{
+		CpuUsage:             host.CPUUsage,
+		MemoryTotalBytes:     host.MemoryTotalBytes,
+		MemoryAvailableBytes: host.MemoryAvailableBytes,
+		Load1:                host.Load1,
+	}
+
+	return status
+}
+
+func (s *server) cacheStatus() *pb.CacheStatus {
+	status := &pb.CacheStatus{
+		Hits:      atomic.LoadUint64(&cacheHit),
+		Misses:    atomic.LoadUint64(&cacheMiss),
+		Coalesced: atomic.LoadUint64(&coalescedTasks),
+
+		StaleHits:   atomic.LoadUint64(&staleHits),
+		StaleErrors: atomic.LoadUint64(&staleErrors),
+	}
+	c, policy := Cache, s.providerConfig.CachePolicy
+	if FileCaching && serverProxy != nil {
+		c, policy = serverProxy.Cache, cache.PolicyLRU
+	}
+	if c != nil {
+		status.Entries = int64(c.Len())
+		status.SizeBytes = c.Size()
+		status.Capacity = c.Capacity()
+		status.Policy = policy
+		status.LogicalSizeBytes = logicalSize(c)
+	}
+	if compressor != nil {
+		status.Compression = compressor.Algorithm()
+	}
+	if FileCaching && serverProxy != nil && serverProxy.Store != nil {
+		status.DiskEntries = int64(serverProxy.Store.Len())
+		status.DiskSizeBytes = serverProxy.Store.Size()
+		status.DiskCapacity = serverProxy.Store.Capacity()
+		status.DiskHits = atomic.LoadUint64(&serverProxy.DiskHit)
+	} else if diskCache != nil {
+		status.DiskEntries = int64(diskCache.Len())
+		status.DiskSizeBytes = diskCache.Size()
+		status.DiskCapacity = diskCache.Capacity()
+		status.DiskHits = atomic.LoadUint64(&diskHit)
+	}
+	if total := status.Hits + status.Misses; total > 0 {
+		status.HitRatio = float64(status.Hits) / float64(total)
+	}
+	return status
+}
+
+// logicalSize returns the size of the results in c before compression.
+func logicalSize(c cache.Cache) int64 {
+	size := int64(0)
+	for _, key := range c.Keys() {
+		if value, found := c.Peek(key); found {
+			size += value.(*cacheEntry).logicalSize()
+		}
+	}
+	return size
+}
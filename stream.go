@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "faasd-agent/proto/agent"
+)
+
+// maxStreamInFlight bounds the number of tasks of a single TaskStream which are
+// executing or waiting to be sent back. When the window is full the agent stops
+// reading from the stream, so gRPC flow control pushes back on the scheduler.
+const maxStreamInFlight = 64
+
+// queueDepth counts the received stream tasks waiting for an execution slot.
+var queueDepth int64
+
+// TaskStream executes every task received on the stream concurrently through
+// the same pipeline as TaskAssign and sends the responses back as soon as each
+// one finishes, so responses may be out of order. Tasks are matched to their
+// responses by correlationId.
+func (s *server) TaskStream(stream pb.TasksRequest_TaskStreamServer) error {
+	return serveTaskStream(stream, s.TaskAssign)
+}
+
+// taskAssigner executes a task, it is TaskAssign.
+type taskAssigner func(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error)
+
+// serveTaskStream runs TaskStream, executing the tasks with assign.
+func serveTaskStream(stream pb.TasksRequest_TaskStreamServer, assign taskAssigner) error {
+	ctx := stream.Context()
+	slots := make(chan struct{}, maxStreamInFlight)
+	// responses never blocks a task: every queued response holds a slot.
+	responses := make(chan *pb.TaskStreamResponse, maxStreamInFlight)
+	sendErr := make(chan error, 1)
+
+	go func() {
+		var err error
+		for res := range responses {
+			if err == nil {
+				err = stream.Send(res)
+				if err != nil {
+					log.Printf("TaskStream send error: %v \n", err)
+				}
+			}
+			<-slots
+		}
+		sendErr <- err
+	}()
+
+	var wg sync.WaitGroup
+	var recvErr error
+	for recvErr == nil {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+
+		atomic.AddInt64(&queueDepth, 1)
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			recvErr = ctx.Err()
+		}
+		atomic.AddInt64(&queueDepth, -1)
+		if recvErr != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(req *pb.TaskStreamRequest) {
+			defer wg.Done()
+			responses <- streamTask(ctx, req, assign)
+		}(req)
+	}
+
+	wg.Wait()
+	close(responses)
+	if err := <-sendErr; err != nil {
+		return err
+	}
+	return recvErr
+}
+
+// streamTask runs a single TaskStream task and wraps its result, errors are
+// reported in the response so one failing task does not close the stream.
+func streamTask(ctx context.Context, req *pb.TaskStreamRequest, assign taskAssigner) *pb.TaskStreamResponse {
+	res := &pb.TaskStreamResponse{CorrelationId: req.CorrelationId}
+	if req.Task == nil {
+		res.Error = "task is empty"
+		return res
+	}
+
+	if req.TimeoutNanoSecond > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutNanoSecond))
+		defer cancel()
+	}
+
+	taskRes, err := assign(ctx, req.Task)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Response = taskRes
+	return res
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "faasd-agent/proto/agent"
+
+	"google.golang.org/grpc"
+)
+
+// taskStream feeds TaskStream with the requests sent on requests until it is
+// closed, and records the responses.
+type taskStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests chan *pb.TaskStreamRequest
+	received int64
+
+	mutex     sync.Mutex
+	responses []*pb.TaskStreamResponse
+}
+
+func newTaskStream() *taskStream {
+	return &taskStream{ctx: context.Background(), requests: make(chan *pb.TaskStreamRequest)}
+}
+
+func (s *taskStream) Context() context.Context { return s.ctx }
+
+func (s *taskStream) Recv() (*pb.TaskStreamRequest, error) {
+	req, ok := <-s.requests
+	if !ok {
+		return nil, io.EOF
+	}
+	atomic.AddInt64(&s.received, 1)
+	return req, nil
+}
+
+func (s *taskStream) Send(res *pb.TaskStreamResponse) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.responses = append(s.responses, res)
+	return nil
+}
+
+func (s *taskStream) correlationIds() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ids := []string{}
+	for _, res := range s.responses {
+		ids = append(ids, res.CorrelationId)
+	}
+	return ids
+}
+
+func streamRequest(id, functionName string, timeout time.Duration) *pb.TaskStreamRequest {
+	return &pb.TaskStreamRequest{
+		CorrelationId:     id,
+		Task:              &pb.TaskRequest{FunctionName: functionName},
+		TimeoutNanoSecond: timeout.Nanoseconds(),
+	}
+}
+
+// serveAsync runs serveTaskStream on stream with assign until the requests
+// are closed.
+func serveAsync(stream *taskStream, assign taskAssigner) chan error {
+	done := make(chan error, 1)
+	go func() { done <- serveTaskStream(stream, assign) }()
+	return done
+}
+
+func echo(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
+	return &pb.TaskResponse{Message: "OK", Response: []byte(in.FunctionName)}, nil
+}
+
+func Test_TaskStreamMatchesOutOfOrderResponses(t *testing.T) {
+	release := make(chan struct{})
+	stream := newTaskStream()
+	done := serveAsync(stream, func(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
+		if in.FunctionName == "slow" {
+			<-release
+		}
+		return echo(ctx, in)
+	})
+
+	stream.requests <- streamRequest("1", "slow", 0)
+	stream.requests <- streamRequest("2", "fast", 0)
+	for len(stream.correlationIds()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	close(stream.requests)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if ids := stream.correlationIds(); strings.Join(ids, ",") != "2,1" {
+		t.Fatalf("expected the fast task to be answered first, got %v", ids)
+	}
+	for _, res := range stream.responses {
+		expected := map[string]string{"1": "slow", "2": "fast"}[res.CorrelationId]
+		if string(res.Response.Response) != expected {
+			t.Fatalf("expected the response of %s to be %s, got %s", res.CorrelationId, expected, res.Response.Response)
+		}
+	}
+}
+
+func Test_TaskStreamTimesOutTasks(t *testing.T) {
+	stream := newTaskStream()
+	done := serveAsync(stream, func(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
+		if in.FunctionName == "hang" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return echo(ctx, in)
+	})
+
+	stream.requests <- streamRequest("1", "hang", 20*time.Millisecond)
+	stream.requests <- streamRequest("2", "fast", 20*time.Millisecond)
+	close(stream.requests)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	for _, res := range stream.responses {
+		switch res.CorrelationId {
+		case "1":
+			if !strings.Contains(res.Error, context.DeadlineExceeded.Error()) || res.Response != nil {
+				t.Fatalf("expected the hanging task to time out, got %+v", res)
+			}
+		case "2":
+			if len(res.Error) > 0 || string(res.Response.Response) != "fast" {
+				t.Fatalf("expected the fast task to succeed, got %+v", res)
+			}
+		}
+	}
+}
+
+func Test_TaskStreamKeepsOpenAfterTaskError(t *testing.T) {
+	stream := newTaskStream()
+	done := serveAsync(stream, func(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
+		if in.FunctionName == "broken" {
+			return nil, errors.New("function failed")
+		}
+		return echo(ctx, in)
+	})
+
+	stream.requests <- streamRequest("1", "broken", 0)
+	stream.requests <- &pb.TaskStreamRequest{CorrelationId: "2"}
+	stream.requests <- streamRequest("3", "fast", 0)
+	close(stream.requests)
+	if err := <-done; err != nil {
+		t.Fatalf("expected failing tasks not to close the stream, got %s", err)
+	}
+
+	errs := map[string]string{}
+	for _, res := range stream.responses {
+		errs[res.CorrelationId] = res.Error
+	}
+	expected := map[string]string{"1": "function failed", "2": "task is empty", "3": ""}
+	if fmt.Sprint(errs) != fmt.Sprint(expected) {
+		t.Fatalf("expected errors %v, got %v", expected, errs)
+	}
+}
+
+func Test_TaskStreamBoundsTasksInFlight(t *testing.T) {
+	release := make(chan struct{})
+	var running int64
+	stream := newTaskStream()
+	done := serveAsync(stream, func(ctx context.Context, in *pb.TaskRequest) (*pb.TaskResponse, error) {
+		atomic.AddInt64(&running, 1)
+		<-release
+		return echo(ctx, in)
+	})
+
+	total := maxStreamInFlight + 2
+	sent := make(chan struct{})
+	go func() {
+		for i := 0; i < total; i++ {
+			stream.requests <- streamRequest(fmt.Sprint(i), "fn", 0)
+		}
+		close(stream.requests)
+		close(sent)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt64(&running) < maxStreamInFlight {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d tasks to run, got %d", maxStreamInFlight, atomic.LoadInt64(&running))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	// the task past the window is read, it waits for a slot, the next one is not read
+	if r, received := atomic.LoadInt64(&running), atomic.LoadInt64(&stream.received); r != maxStreamInFlight || received != maxStreamInFlight+1 {
+		t.Fatalf("expected %d tasks running and %d received while the window is full, got %d and %d",
+			maxStreamInFlight, maxStreamInFlight+1, r, received)
+	}
+
+	close(release)
+	<-sent
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if answered := len(stream.correlationIds()); answered != total {
+		t.Fatalf("expected %d responses, got %d", total, answered)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"faasd-agent/pkg/cache"
+)
+
+// diskCache is the persistent second tier of Cache, nil when disabled. Every
+// result is written through to it, and results found only on disk are
+// promoted to memory.
+var diskCache *cache.Disk
+var diskHit uint64
+
+// tierMutex orders the disk writes of results with their removals and with
+// the promotions of results from disk, so a removed result never comes back.
+var tierMutex sync.Mutex
+
+// pending holds the results added to memory and not yet written to disk by
+// persistResult, and the results evicted from memory with hits not yet
+// written. A result replaced or removed before it is written is dropped from
+// pending and never written.
+var pending = make(map[string]*cacheEntry)
+var pendingMutex sync.Mutex
+
+// storedEntry is the encoding of a cacheEntry in the disk cache.
+type storedEntry struct {
+	Response      []byte
+	CreatedAt     time.Time
+	ExecutionTime time.Duration
+	ExpiresAt     time.Time
+	StaleUntil    time.Time
+	Vary          []string
+	FunctionName  string
+	Image         string
+	Hits          uint64
+	// Encoding is the compression of Response, RawSize its size before compression
+	Encoding string
+	RawSize  int64
+}
+
+func encodeEntry(e *cacheEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(storedEntry{
+		Response:      e.response,
+		CreatedAt:     e.createdAt,
+		ExecutionTime: e.executionTime,
+		ExpiresAt:     e.expiresAt,
+		StaleUntil:    e.staleUntil,
+		Vary:          e.vary,
+		FunctionName:  e.functionName,
+		Image:         e.image,
+		Hits:          atomic.LoadUint64(&e.hits),
+		Encoding:      e.encoding,
+		RawSize:       e.rawSize,
+	})
+	return buf.Bytes(), err
+}
+
+func decodeEntry(data []byte) (*cacheEntry, error) {
+	var stored storedEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&stored); err != nil {
+		return nil, err
+	}
+	return &cacheEntry{
+		response:      stored.Response,
+		createdAt:     stored.CreatedAt,
+		executionTime: stored.ExecutionTime,
+		expiresAt:     stored.ExpiresAt,
+		staleUntil:    stored.StaleUntil,
+		vary:          stored.Vary,
+		functionName:  stored.FunctionName,
+		image:         stored.Image,
+		hits:          stored.Hits,
+		storedHits:    stored.Hits,
+		encoding:      stored.Encoding,
+		rawSize:       stored.RawSize,
+	}, nil
+}
+
+// getResult returns the cached entry of key from memory or, promoting it to
+// memory, from disk.
+func getResult(key string) (interface{}, bool) {
+	if value, found := Cache.Get(key); found {
+		return value, true
+	}
+	tierMutex.Lock()
+	defer tierMutex.Unlock()
+	// an entry evicted with hits not yet written is newer than its disk copy
+	pendingMutex.Lock()
+	entry, found := pending[key]
+	pendingMutex.Unlock()
+	if !found {
+		entry, found = loadResult(key, true)
+	}
+	if !found {
+		return nil, false
+	}
+	Cache.Add(key, entry, entry.size())
+	atomic.AddUint64(&diskHit, 1)
+	return entry, true
+}
+
+// peekResult returns the cached entry of key from memory or disk without
+// changing its recency.
+func peekResult(key string) (interface{}, bool) {
+	if value, found := Cache.Peek(key); found {
+		return value, true
+	}
+	entry, found := loadResult(key, false)
+	if !found {
+		return nil, false
+	}
+	return entry, true
+}
+
+func loadResult(key string, touch bool) (*cacheEntry, bool) {
+	if diskCache == nil {
+		return nil, false
+	}
+
+	var data []byte
+	var found bool
+	if touch {
+		data, found = diskCache.Get(key)
+	} else {
+		data, found = diskCache.Peek(key)
+	}
+	if !found {
+		return nil, false
+	}
+
+	entry, err := decodeEntry(data)
+	if err != nil {
+		log.Printf("[Cache] dropping undecodable disk entry %s: %s\n", key, err)
+		diskCache.Remove(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+// addResult adds the entry of key to memory and queues it for persistResult,
+// replacing the previous entry of key.
+func addResult(key string, entry *cacheEntry) bool {
+	if diskCache != nil {
+		pendingMutex.Lock()
+		pending[key] = entry
+		pendingMutex.Unlock()
+	}
+	return Cache.Add(key, entry, entry.size())
+}
+
+// dropPending stops the entry of key from being written to disk. The caller
+// holds tierMutex.
+func dropPending(key string) {
+	pendingMutex.Lock()
+	delete(pending, key)
+	pendingMutex.Unlock()
+}
+
+// removeResult removes key from both tiers.
+func removeResult(key string) {
+	tierMutex.Lock()
+	defer tierMutex.Unlock()
+	// the hits of a removed result are dropped with it, but an eviction by a
+	// concurrent add may queue them until key leaves memory
+	Cache.Remove(key)
+	dropPending(key)
+	if diskCache != nil {
+		diskCache.Remove(key)
+	}
+}
+
+// removeResultIf removes key from both tiers when it still holds entry, and
+// reports whether it did, so a newer result of key is kept. The caller holds
+// mutex.
+func removeResultIf(key string, entry *cacheEntry) bool {
+	tierMutex.Lock()
+	defer tierMutex.Unlock()
+	if value, found := Cache.Peek(key); found && !sameResult(value.(*cacheEntry), entry) {
+		return false
+	}
+	pendingMutex.Lock()
+	queued, found := pending[key]
+	pendingMutex.Unlock()
+	if found && !sameResult(queued, entry) {
+		return false
+	}
+	if stored, found := loadResult(key, false); found && !sameResult(stored, entry) {
+		return false
+	}
+
+	Cache.Remove(key)
+	dropPending(key)
+	if diskCache != nil {
+		diskCache.Remove(key)
+	}
+	return true
+}
+
+// sameResult reports whether a and b are the same result, possibly read
+// from disk separately.
+func sameResult(a, b *cacheEntry) bool {
+	return a == b || a.createdAt.Equal(b.createdAt)
+}
+
+// persistResult writes the entry of key, added with addResult, to the disk
+// cache, if enabled. It is called without holding mutex, and does nothing
+// when the entry was replaced or removed since it was added.
+func persistResult(key string, entry *cacheEntry) {
+	if diskCache == nil {
+		return
+	}
+	tierMutex.Lock()
+	defer tierMutex.Unlock()
+	pendingMutex.Lock()
+	queued := pending[key] == entry
+	if queued {
+		delete(pending, key)
+	}
+	pendingMutex.Unlock()
+	if !queued {
+		return
+	}
+
+	hits := atomic.LoadUint64(&entry.hits)
+	data, err := encodeEntry(entry)
+	if err == nil {
+		err = diskCache.Put(key, data, entry.keepUntil())
+	}
+	if err != nil {
+		log.Printf("[Cache] cannot persist %s: %s\n", key, err)
+		return
+	}
+	atomic.StoreUint64(&entry.storedHits, hits)
+}
+
+// onResultEvicted queues a result evicted from memory for persistResult when
+// it has hits not yet written to disk, so its disk copy keeps counting them.
+func onResultEvicted(key string, value interface{}) {
+	entry := value.(*cacheEntry)
+	if diskCache == nil || entry.removable(time.Now()) ||
+		atomic.LoadUint64(&entry.hits) == atomic.LoadUint64(&entry.storedHits) {
+		return
+	}
+	pendingMutex.Lock()
+	defer pendingMutex.Unlock()
+	// a pending newer entry of key supersedes the evicted one
+	if _, queued := pending[key]; !queued {
+		pending[key] = entry
+	}
+}
+
+// flushPending writes the pending results to disk. It is called without
+// holding mutex.
+func flushPending() {
+	if diskCache == nil {
+		return
+	}
+	pendingMutex.Lock()
+	queued := make(map[string]*cacheEntry, len(pending))
+	for key, entry := range pending {
+		queued[key] = entry
+	}
+	pendingMutex.Unlock()
+	for key, entry := range queued {
+		persistResult(key, entry)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"faasd-agent/pkg/cache"
+	"faasd-agent/pkg/compress"
+)
+
+// setupTestCache replaces the result cache with an empty one, with a disk
+// tier when withDisk is set, for the duration of the test.
+func setupTestCache(t *testing.T, withDisk bool) {
+	previousCache, previousDisk, previousCompressor := Cache, diskCache, compressor
+	t.Cleanup(func() {
+		if diskCache != nil {
+			diskCache.Close()
+		}
+		Cache, diskCache, compressor = previousCache, previousDisk, previousCompressor
+		pending = make(map[string]*cacheEntry)
+	})
+
+	var err error
+	if Cache, err = cache.New(cache.PolicyLRU, 1<<20, onResultEvicted); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if compressor, err = compress.New(compress.None, 0); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	diskCache = nil
+	if withDisk {
+		if diskCache, err = cache.OpenDisk(filepath.Join(t.TempDir(), "cache.db"), 1<<20); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+}
+
+func testEntry(t *testing.T, functionName, image, response string) *cacheEntry {
+	entry := &cacheEntry{createdAt: time.Now(), functionName: functionName, image: image}
+	if err := entry.setResponse([]byte(response)); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	return entry
+}
+
+func Test_PersistResultSkipsRemovedResult(t *testing.T) {
+	setupTestCache(t, true)
+
+	entry := testEntry(t, "fn", "", "result")
+	addResult("k", entry)
+	removeResult("k")
+	persistResult("k", entry)
+
+	if _, found := diskCache.Peek("k"); found {
+		t.Fatalf("expected the removed result not to be written to disk")
+	}
+	if _, found := getResult("k"); found {
+		t.Fatalf("expected the removed result not to be found")
+	}
+}
+
+func Test_PersistResultSkipsReplacedResult(t *testing.T) {
+	setupTestCache(t, true)
+
+	older, newer := testEntry(t, "fn", "", "older"), testEntry(t, "fn", "", "newer")
+	addResult("k", older)
+	addResult("k", newer)
+	persistResult("k", newer)
+	persistResult("k", older)
+
+	Cache.Purge()
+	value, found := getResult("k")
+	if !found {
+		t.Fatalf("expected the result to be promoted from disk")
+	}
+	if body, _ := value.(*cacheEntry).body(); string(body) != "newer" {
+		t.Fatalf("expected the newer result on disk, got %q", body)
+	}
+}
+
+func Test_PersistResultWritesEvictedResult(t *testing.T) {
+	setupTestCache(t, true)
+
+	entry := testEntry(t, "fn", "", "result")
+	addResult("k", entry)
+	Cache.Purge()
+	persistResult("k", entry)
+
+	if _, found := getResult("k"); !found {
+		t.Fatalf("expected the result evicted from memory to be written to disk")
+	}
+}
+
+func Test_PromotedResultKeepsItsHits(t *testing.T) {
+	setupTestCache(t, true)
+
+	entry := testEntry(t, "fn", "", "result")
+	entry.hits = 3
+	addResult("k", entry)
+	persistResult("k", entry)
+
+	Cache.Purge()
+	value, found := getResult("k")
+	if !found {
+		t.Fatalf("expected the result to be promoted from disk")
+	}
+	if hits := atomic.LoadUint64(&value.(*cacheEntry).hits); hits != 3 {
+		t.Fatalf("expected the promoted result to keep its 3 hits, got %d", hits)
+	}
+}
+
+func Test_EvictedResultWritesItsHits(t *testing.T) {
+	setupTestCache(t, true)
+	first, second := testEntry(t, "fn", "", "first"), testEntry(t, "fn", "", "other")
+	var err error
+	// room for a single result
+	if Cache, err = cache.New(cache.PolicyLRU, first.size(), onResultEvicted); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	addResult("first", first)
+	flushPending()
+	atomic.AddUint64(&first.hits, 2)
+	addResult("second", second)
+	flushPending()
+
+	if _, found := Cache.Peek("first"); found {
+		t.Fatalf("expected the first result to be evicted from memory")
+	}
+	entry, found := loadResult("first", false)
+	if !found {
+		t.Fatalf("expected the first result on disk")
+	}
+	if entry.hits != 2 {
+		t.Fatalf("expected the hits of the evicted result on disk, got %d", entry.hits)
+	}
+}
+
+func Test_RemoveResultIfKeepsNewerResult(t *testing.T) {
+	setupTestCache(t, true)
+
+	verified := testEntry(t, "fn", "", "verified")
+	addResult("k", verified)
+	flushPending()
+	newer := testEntry(t, "fn", "", "newer")
+	newer.createdAt = verified.createdAt.Add(time.Second)
+	addResult("k", newer)
+	flushPending()
+
+	if removeResultIf("k", verified) {
+		t.Fatalf("expected the newer result not to be removed")
+	}
+	Cache.Purge()
+	if removeResultIf("k", verified) {
+		t.Fatalf("expected the newer result not to be removed from disk")
+	}
+	if value, found := getResult("k"); !found || value.(*cacheEntry) == verified {
+		t.Fatalf("expected the newer result to remain")
+	}
+}
+
+func Test_RemoveResultIfRemovesVerifiedResult(t *testing.T) {
+	for _, inMemory := range []bool{false, true} {
+		t.Run(fmt.Sprintf("in memory: %v", inMemory), func(t *testing.T) {
+			setupTestCache(t, true)
+
+			verified := testEntry(t, "fn", "", "verified")
+			addResult("k", verified)
+			flushPending()
+			if !inMemory {
+				Cache.Purge()
+			}
+
+			if !removeResultIf("k", verified) {
+				t.Fatalf("expected the verified result to be removed")
+			}
+			if keys := cachedKeys(); len(keys) != 0 {
+				t.Fatalf("expected the verified result removed from both tiers, got %v", keys)
+			}
+		})
+	}
+}
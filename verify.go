@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"faasd-agent/pkg/httpcache"
+)
+
+const (
+	// maxConcurrentVerifications bounds the background re-executions, samples
+	// taken while they are all busy are skipped
+	maxConcurrentVerifications = 4
+	// verifyTimeout bounds one re-execution
+	verifyTimeout = time.Minute
+)
+
+var verifySlots = make(chan struct{}, maxConcurrentVerifications)
+
+// uncacheable holds the functions whose cached results diverged too often,
+// their results are no longer cached.
+var uncacheable sync.Map
+
+// sampleVerification re-executes, for a sample of the cache hits, the task
+// answered with entry in the background and compares the results.
+func (s *server) sampleVerification(functionName, extraPath string, req *http.Request, body []byte, key string, entry *cacheEntry) {
+	if s.providerConfig.CacheVerifyRate <= 0 || rand.Float64() >= s.providerConfig.CacheVerifyRate {
+		return
+	}
+	select {
+	case verifySlots <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-verifySlots }()
+		s.verify(functionName, extraPath, req, body, key, entry)
+	}()
+}
+
+// verify re-executes the task and compares its result with the cached one.
+// A divergent result is removed from the cache unless replaced meanwhile, and
+// the function stops being cached once its divergence rate crosses the
+// threshold.
+func (s *server) verify(functionName, extraPath string, req *http.Request, body []byte, key string, entry *cacheEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	response, _, err := s.invoke(ctx, functionName, extraPath, req, body)
+	if err != nil {
+		log.Printf("[Verify] cannot re-execute %s: %s\n", functionName, err)
+		return
+	}
+
+	cached, err := entry.body()
+	if err != nil {
+		log.Printf("[Verify] cannot decompress the cached result of %s: %s\n", functionName, err)
+		return
+	}
+
+	diverged := !httpcache.Equivalent(cached, response)
+	verifications, divergences := tracker.ObserveVerification(functionName, diverged)
+	if !diverged {
+		return
+	}
+
+	// the task ran meanwhile, key may hold a newer result than the verified one
+	mutex.Lock()
+	removeResultIf(key, entry)
+	mutex.Unlock()
+	rate := float64(divergences) / float64(verifications)
+	log.Printf("[Verify] cached result of %s diverged, divergences: %d/%d\n", functionName, divergences, verifications)
+
+	threshold := s.providerConfig.CacheVerifyThreshold
+	if threshold > 0 && verifications >= int64(s.providerConfig.CacheVerifyMinSamples) && rate > threshold {
+		if _, disabled := uncacheable.LoadOrStore(functionName, true); !disabled {
+			log.Printf("[Verify] %s is no longer cached, divergence rate %f exceeds %f\n", functionName, rate, threshold)
+		}
+	}
+}
+
+// cacheDisabled reports whether the function stopped being cached after diverging.
+func cacheDisabled(functionName string) bool {
+	_, disabled := uncacheable.Load(functionName)
+	return disabled
+}